@@ -0,0 +1,147 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetAliasCommand returns the alias command
+func GetAliasCommand() *cobra.Command {
+	aliasCmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage the shortcuts for plugins and commands.",
+		Long: `Manage the shortcuts for plugins and commands.
+
+    An alias expands to a command and its leading args. Any args after the alias are appended to them.
+    Plugins can also declare aliases of their own in their metadata.
+    Aliases can't have the same name as a built-in command or a plugin, and they can't expand to other aliases.
+
+    Example: konveyor alias set m2k 'move2kube transform --qa-skip'
+    Then "konveyor m2k -s src" runs "konveyor move2kube transform --qa-skip -s src"
+`,
+	}
+	aliasCmd.AddCommand(GetAliasSetSubCommand())
+	aliasCmd.AddCommand(GetAliasUnsetSubCommand())
+	aliasCmd.AddCommand(GetAliasListSubCommand())
+	return aliasCmd
+}
+
+// GetAliasSetSubCommand returns a command to add or update an alias.
+func GetAliasSetSubCommand() *cobra.Command {
+	aliasSetCmd := &cobra.Command{
+		Use:   "set <name> <command>...",
+		Args:  cobra.MinimumNArgs(2),
+		Short: "Add or update an alias.",
+		Long: `Add or update an alias.
+
+    The command can be given as a single quoted string or as separate args.
+    Example: konveyor alias set m2k 'move2kube transform --qa-skip'
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			name, command := args[0], args[1:]
+			if len(command) == 1 {
+				var err error
+				command, err = common.SplitArgs(command[0])
+				if err != nil {
+					logrus.Fatalf("the command for the alias '%s' is invalid. Error: %q", name, err)
+				}
+			}
+			if c, _, err := cmd.Root().Find(command); err != nil || c == cmd.Root() {
+				if _, _, err := plugin.FindPluginForArgs(command); err != nil {
+					logrus.Warnf("'%s' is neither a built-in command nor an available plugin", command[0])
+				}
+			}
+			if err := plugin.SetUserAlias(name, command); err != nil {
+				logrus.Fatalf("failed to set the alias '%s'. Error: %q", name, err)
+			}
+			logrus.Infof("The alias '%s' was set to: %s", name, common.JoinArgs(command))
+		},
+	}
+	// Flags after the alias name belong to the command of the alias.
+	aliasSetCmd.Flags().SetInterspersed(false)
+	return aliasSetCmd
+}
+
+// GetAliasUnsetSubCommand returns a command to remove an alias.
+func GetAliasUnsetSubCommand() *cobra.Command {
+	aliasUnsetCmd := &cobra.Command{
+		Use:   "unset <name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Remove an alias.",
+		Long:  "Remove an alias. The aliases declared by plugins are removed along with the plugin.",
+		Run: func(_ *cobra.Command, args []string) {
+			name := args[0]
+			if err := plugin.UnsetUserAlias(name); err != nil {
+				if errors.Is(err, types.ErrAliasNotFound) {
+					if alias, err := plugin.FindAlias(name); err == nil && alias.Source == types.ALIAS_SOURCE_PLUGIN {
+						logrus.Fatalf("the alias '%s' is declared by the plugin '%s' and can't be removed", name, alias.Command[0])
+					}
+				}
+				logrus.Fatalf("failed to remove the alias '%s'. Error: %q", name, err)
+			}
+			logrus.Infof("The alias '%s' was removed", name)
+		},
+	}
+	return aliasUnsetCmd
+}
+
+// GetAliasListSubCommand returns a command to list the aliases.
+func GetAliasListSubCommand() *cobra.Command {
+	aliasListCmd := &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List the aliases.",
+		Long:  "List the aliases defined by you and the ones declared by the installed plugins.",
+		Run: func(cmd *cobra.Command, _ []string) {
+			aliases, err := plugin.GetAliases()
+			if err != nil {
+				logrus.Fatalf("failed to get the aliases. Error: %q", err)
+			}
+			if output := getOutputFormat(cmd); output != "" {
+				outputStr, err := formatOutput(output, aliases)
+				if err != nil {
+					logrus.Fatalf("failed to format the aliases. Error: %q", err)
+				}
+				fmt.Print(outputStr)
+				return
+			}
+			if len(aliases) == 0 {
+				logrus.Infof("No aliases were found.")
+				return
+			}
+			w := &strings.Builder{}
+			tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tSOURCE\tCOMMAND")
+			for _, alias := range aliases {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", alias.Name, alias.Source, common.JoinArgs(alias.Command))
+			}
+			tw.Flush()
+			fmt.Print(w.String())
+		},
+	}
+	return aliasListCmd
+}
@@ -17,92 +17,287 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
+	"unicode/utf8"
 
-	"github.com/konveyor/cli/lib/cache"
-	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/config"
+	"github.com/konveyor/cli/lib/history"
 	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/stats"
 	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// errHelpFlag is returned when the help flag is found before the command name.
+var errHelpFlag = errors.New("the help flag was found")
+
 // Execute is the start of the flow. It finds an executes the appropriate command based on the args.
 func Execute() error {
+	if err := config.Load(); err != nil {
+		logrus.Warnf("failed to load the config file. Using the defaults. Error: %q", err)
+	}
+	applyConfig()
 	rootCmd := GetRootCommand()
-	if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil && cmd != nil {
+	if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil && cmd != nil && cmd != rootCmd {
 		return rootCmd.Execute()
 	}
 
-	cmdName := "" // first "non-flag" arguments
-	rest := []string{}
-	for i, arg := range os.Args[1:] {
-		if !strings.HasPrefix(arg, "-") {
-			cmdName = arg
-			rest = os.Args[1+i+1:]
-			break
+	// Parse the root flags that come before the command name.
+	args, err := parseRootFlags(rootCmd, os.Args[1:])
+	if err != nil {
+		if errors.Is(err, errHelpFlag) {
+			addPluginCommands(rootCmd)
+			// Cobra would reject single dash forms like -help.
+			rootCmd.SetArgs([]string{"--help"})
+			return rootCmd.Execute()
 		}
+		return err
 	}
-
-	if cmdName == "" || cmdName == "help" || cmdName == "completion" || cmdName == cobra.ShellCompRequestCmd || cmdName == cobra.ShellCompNoDescRequestCmd {
+	if len(args) == 0 {
+		// Show the plugins in the help.
+		addPluginCommands(rootCmd)
+		return rootCmd.Execute()
+	}
+	cmdName := args[0]
+	if cmdName == "help" || cmdName == cobra.ShellCompRequestCmd || cmdName == cobra.ShellCompNoDescRequestCmd {
+		addPluginCommands(rootCmd)
+		return rootCmd.Execute()
+	}
+	if cmdName == "completion" {
 		return rootCmd.Execute()
 	}
 
-	// Search for a plugin if no command is found.
+	// Apply the root flags to the dispatcher itself.
+	if err := rootCmd.PersistentPreRunE(rootCmd, nil); err != nil {
+		return err
+	}
 
-	// Look in the local cache.
-	localCache, err := cache.GetLocalCache()
+	// Expand the alias before looking for a plugin.
+	expanded, ok, err := plugin.ExpandAlias(args)
 	if err != nil {
-		return fmt.Errorf("failed to get the local cache. Error: %w", err)
+		logrus.Debugf("failed to look for an alias named '%s'. Error: %q", cmdName, err)
 	}
-	pluginPath := ""
-	if len(localCache.Spec.Installed) > 0 {
-		idx := common.FindIndex(func(p types.InstalledPlugin) bool { return p.Name == cmdName }, localCache.Spec.Installed)
-		if idx != -1 {
-			pluginPath = plugin.GetPluginBinPath(localCache.Spec.Installed[idx])
+	if ok {
+		args = expanded
+		logrus.Debugf("Expanded the alias '%s' to: %+v", cmdName, args)
+		if cmd, _, err := rootCmd.Find(args); err == nil && cmd != nil && cmd != rootCmd {
+			rootCmd.SetArgs(args)
+			return rootCmd.Execute()
 		}
+		cmdName = args[0]
 	}
 
-	// Look in the PATH.
-	if pluginPath == "" {
-		pluginPaths, err := plugin.GetPluginsListFromPath(false)
-		if err != nil {
-			return fmt.Errorf("failed to get the list of plugins from the PATH. Error: %w", err)
-		}
-		idx := common.FindIndex(func(p string) bool { return filepath.Base(p) == types.VALID_PLUGIN_FILENAME_PREFIX+cmdName }, pluginPaths)
-		if idx != -1 {
-			pluginPath = pluginPaths[idx]
+	// Search for a plugin if no command is found.
+	p, rest, err := plugin.FindPluginForArgs(args)
+	if err != nil {
+		if errors.Is(err, types.ErrPluginNotFound) {
+			if plugin.IsPluginGroup(cmdName) {
+				// Show the nested plugins in the help.
+				addPluginCommands(rootCmd)
+				return rootCmd.Execute()
+			}
+			return fmt.Errorf("unknown command '%s'", cmdName)
 		}
+		return fmt.Errorf("failed to look for a plugin named '%s'. Error: %w", cmdName, err)
 	}
+	return runPlugin(rootCmd, p, rest)
+}
 
-	// No plugin found
-	if pluginPath == "" {
-		return fmt.Errorf("unknown command '%s'", cmdName)
+// runPlugin runs the plugin with the given args after checking its requirements.
+// The plugin replaces konveyor unless it has to be run as a child process to sandbox it or record its run in the history.
+// Both running a plugin directly and running it through its stub command end up here.
+func runPlugin(rootCmd *cobra.Command, p types.DiscoveredPlugin, rest []string) error {
+	if p.Installed != nil {
+		if err := plugin.CheckInstalledPluginRequirements(*p.Installed); err != nil {
+			return fmt.Errorf("cannot run the plugin '%s'. Error: %w", p.Name, err)
+		}
 	}
 
-	logrus.Infof("Executing the plugin '%s' with the args: %+v", pluginPath, rest)
-	if err := ExecutePlugin(pluginPath, rest, os.Environ()); err != nil {
+	environment := getPluginEnvironment(rootCmd, p)
+	executable, cmdArgs, err := plugin.GetPluginCommand(p, rest, environment, true)
+	if err != nil {
+		return fmt.Errorf("cannot run the plugin '%s'. Error: %w", p.Name, err)
+	}
+	logrus.Infof("Executing the plugin '%s' with the args: %+v", executable, cmdArgs)
+	sandbox, sandboxed := plugin.GetSandbox(p)
+	if !sandboxed && !history.IsEnabled() && runtime.GOOS != "windows" {
+		// The duration is unknown since konveyor is replaced by the plugin.
+		recordStats(p, time.Now(), 0, false)
+		if err := ExecutePlugin(executable, cmdArgs, environment); err != nil {
+			return fmt.Errorf("the plugin failed to run or did not exit properly. Error: %w", err)
+		}
+		return nil
+	}
+	// Run the plugin as a child process so that we can record how it exited.
+	start := time.Now()
+	exitCode, runErr := RunPlugin(executable, cmdArgs, environment, sandbox, sandboxed)
+	if history.IsEnabled() {
+		recordRun(p, rest, start, exitCode, runErr)
+	}
+	recordStats(p, start, time.Since(start), true)
+	if err := getPluginExitError(exitCode, runErr); err != nil {
 		return fmt.Errorf("the plugin failed to run or did not exit properly. Error: %w", err)
 	}
 	return nil
 }
 
+// getPluginExitError returns a PluginExitError if the plugin ran and exited with a non-zero exit code
+// so that konveyor exits with the same code. Other errors are returned as is.
+func getPluginExitError(exitCode int, runErr error) error {
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		return &types.PluginExitError{Code: exitCode}
+	}
+	return runErr
+}
+
+// recordStats counts the run of the plugin in the usage statistics if they are enabled.
+func recordStats(p types.DiscoveredPlugin, start time.Time, duration time.Duration, timed bool) {
+	if !stats.IsEnabled() {
+		return
+	}
+	if err := stats.Record(p.Name, start, duration, timed); err != nil {
+		logrus.Debugf("failed to record the usage of the plugin '%s'. Error: %q", p.Name, err)
+	}
+}
+
+// recordRun adds the run of the plugin to the history.
+func recordRun(p types.DiscoveredPlugin, args []string, start time.Time, exitCode int, runErr error) {
+	entry := types.HistoryEntry{
+		Timestamp:  start.UTC(),
+		Plugin:     p.Name,
+		Path:       p.Path,
+		Args:       history.RedactArgs(args),
+		ExitCode:   exitCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if p.Installed != nil {
+		entry.Version = p.Installed.Version
+		if p.Installed.Image != "" {
+			entry.Path = p.Installed.Image
+		}
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		entry.Cwd = cwd
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		entry.Error = runErr.Error()
+	}
+	if err := history.Record(entry); err != nil {
+		logrus.Warnf("failed to record the run of the plugin '%s' in the history. Error: %q", p.Name, err)
+	}
+}
+
+// getPluginEnvironment returns the environment variables that the plugin should be run with.
+func getPluginEnvironment(rootCmd *cobra.Command, p types.DiscoveredPlugin) []string {
+	output, _ := rootCmd.PersistentFlags().GetString("output")
+	return plugin.GetPluginEnvironment(p, output)
+}
+
+// parseRootFlags parses and sets the persistent flags of the root command that come before the command name.
+// It returns the remaining args starting from the command name. A "--" ends the root flags.
+func parseRootFlags(rootCmd *cobra.Command, args []string) ([]string, error) {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args[i+1:], nil
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return args[i:], nil
+		}
+		if !strings.HasPrefix(arg, "--") {
+			consumed, err := parseRootShorthands(flags, arg, args[i+1:])
+			if err != nil {
+				return nil, err
+			}
+			i += consumed
+			continue
+		}
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		if name == "help" {
+			return nil, errHelpFlag
+		}
+		f := flags.Lookup(name)
+		if f == nil {
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		}
+		if !hasValue {
+			if f.NoOptDefVal != "" {
+				value = f.NoOptDefVal
+			} else {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("flag needs an argument: %s", arg)
+				}
+				i++
+				value = args[i]
+			}
+		}
+		if err := flags.Set(f.Name, value); err != nil {
+			return nil, fmt.Errorf("invalid argument '%s' for the flag %s . Error: %w", value, arg, err)
+		}
+	}
+	return nil, nil
+}
+
+// parseRootShorthands parses an arg containing one or more shorthand flags of the root command, like pflag does.
+// Example: -v, -ojson, -o=json and -vo json where o takes a value.
+// It returns the number of the following args that were consumed as the value of the last flag.
+func parseRootShorthands(flags *pflag.FlagSet, arg string, rest []string) (int, error) {
+	shorthands := arg[1:]
+	for shorthands != "" {
+		r, size := utf8.DecodeRuneInString(shorthands)
+		c := shorthands[:size]
+		shorthands = shorthands[size:]
+		var f *pflag.Flag
+		if size == 1 && r != '=' {
+			f = flags.ShorthandLookup(c)
+		}
+		if (f == nil && c == "h") || (f != nil && f.Name == "help") {
+			return 0, errHelpFlag
+		}
+		if f == nil {
+			return 0, fmt.Errorf("unknown shorthand flag: '%s' in %s", c, arg)
+		}
+		if f.NoOptDefVal != "" && !strings.HasPrefix(shorthands, "=") {
+			if err := flags.Set(f.Name, f.NoOptDefVal); err != nil {
+				return 0, fmt.Errorf("invalid argument '%s' for the flag -%s . Error: %w", f.NoOptDefVal, c, err)
+			}
+			continue
+		}
+		// The rest of the arg is the value, otherwise the next arg is.
+		value, consumed := strings.TrimPrefix(shorthands, "="), 0
+		if shorthands == "" {
+			if len(rest) == 0 {
+				return 0, fmt.Errorf("flag needs an argument: '%s' in %s", c, arg)
+			}
+			value, consumed = rest[0], 1
+		}
+		if err := flags.Set(f.Name, value); err != nil {
+			return 0, fmt.Errorf("invalid argument '%s' for the flag -%s . Error: %w", value, c, err)
+		}
+		return consumed, nil
+	}
+	return 0, nil
+}
+
 // ExecutePlugin executes a plugin given the path to the binary, args and environment variables
 func ExecutePlugin(executablePath string, cmdArgs, environment []string) error {
 	// Windows does not support exec syscall.
 	if runtime.GOOS == "windows" {
-		cmd := Command(executablePath, cmdArgs...)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		cmd.Stdin = os.Stdin
-		cmd.Env = environment
-		return cmd.Run()
+		return getPluginExitError(RunPlugin(executablePath, cmdArgs, environment, types.Sandbox{}, false))
 	}
 
 	// invoke cmd binary relaying the environment and args given
@@ -110,6 +305,56 @@ func ExecutePlugin(executablePath string, cmdArgs, environment []string) error {
 	return syscall.Exec(executablePath, append([]string{executablePath}, cmdArgs...), environment)
 }
 
+// RunPlugin runs a plugin as a child process and waits for it to exit. It returns the exit code of the plugin.
+// SIGINT, SIGTERM and SIGHUP received while the plugin is running are forwarded to it instead of stopping konveyor.
+// If sandboxed is true the plugin is run with the restrictions of the sandbox. It is not run at all if the sandbox is not available.
+func RunPlugin(executablePath string, cmdArgs, environment []string, sandbox types.Sandbox, sandboxed bool) (int, error) {
+	cmd := Command(executablePath, cmdArgs...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	cmd.Env = environment
+	ownGroup := preparePluginProcess(cmd)
+	// Start listening before the plugin starts so that no signal is missed.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+	if sandboxed {
+		if err := plugin.StartInSandbox(cmd, sandbox); err != nil {
+			if errors.Is(err, types.ErrSandboxUnavailable) {
+				return -1, fmt.Errorf("refusing to run the plugin without the sandbox since sandbox.enabled is set. Set it to false to run the plugin anyway. Error: %w", err)
+			}
+			return -1, err
+		}
+	} else if err := cmd.Start(); err != nil {
+		return -1, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				logrus.Debugf("Forwarding the signal %s to the plugin", sig)
+				forwardSignal(cmd.Process, sig, ownGroup)
+			case <-done:
+				return
+			}
+		}
+	}()
+	err := cmd.Wait()
+	return getExitCode(cmd.ProcessState), err
+}
+
+// getExitCode returns the exit code of the process.
+// A process killed by a signal gets 128 plus the signal number, like in a shell.
+func getExitCode(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return state.ExitCode()
+}
+
 // Command executes the given command on Windows
 func Command(name string, arg ...string) *exec.Cmd {
 	cmd := &exec.Cmd{
@@ -0,0 +1,135 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/spf13/cobra"
+)
+
+func getTestRootCommand() *cobra.Command {
+	rootCmd := &cobra.Command{Use: "konveyor"}
+	rootCmd.PersistentFlags().String("log-level", "info", "")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "")
+	return rootCmd
+}
+
+func TestParseRootFlags(t *testing.T) {
+	testCases := []struct {
+		name       string
+		args       []string
+		wantRest   []string
+		wantErr    error
+		wantAnyErr bool
+		output     string
+		logLevel   string
+		verbose    bool
+	}{
+		{name: "no flags", args: []string{"foo", "-o", "x"}, wantRest: []string{"foo", "-o", "x"}},
+		{name: "separate short value", args: []string{"-o", "json", "foo"}, wantRest: []string{"foo"}, output: "json"},
+		{name: "attached short value", args: []string{"-ojson", "foo"}, wantRest: []string{"foo"}, output: "json"},
+		{name: "short value with equals", args: []string{"-o=json", "foo"}, wantRest: []string{"foo"}, output: "json"},
+		{name: "long value", args: []string{"--output", "yaml", "--log-level=debug", "foo"}, wantRest: []string{"foo"}, output: "yaml", logLevel: "debug"},
+		{name: "bool shorthand", args: []string{"-v", "foo"}, wantRest: []string{"foo"}, verbose: true},
+		{name: "bool shorthand with value", args: []string{"-v=false", "foo"}, wantRest: []string{"foo"}},
+		{name: "combined shorthands", args: []string{"-vojson", "foo"}, wantRest: []string{"foo"}, output: "json", verbose: true},
+		{name: "combined shorthands with next value", args: []string{"-vo", "json", "foo"}, wantRest: []string{"foo"}, output: "json", verbose: true},
+		{name: "double dash", args: []string{"-v", "--", "-o"}, wantRest: []string{"-o"}, verbose: true},
+		{name: "short help", args: []string{"-h"}, wantErr: errHelpFlag},
+		{name: "long help", args: []string{"--help"}, wantErr: errHelpFlag},
+		{name: "single dash help", args: []string{"-help"}, wantErr: errHelpFlag},
+		{name: "help after bool shorthand", args: []string{"-vh"}, wantErr: errHelpFlag, verbose: true},
+		{name: "unknown shorthand", args: []string{"-x", "foo"}, wantAnyErr: true},
+		{name: "unknown long shorthand", args: []string{"-xyz", "foo"}, wantAnyErr: true},
+		{name: "unknown multibyte shorthand", args: []string{"-é"}, wantAnyErr: true},
+		{name: "equals as shorthand", args: []string{"-=x"}, wantAnyErr: true},
+		{name: "unknown long flag", args: []string{"--nope", "foo"}, wantAnyErr: true},
+		{name: "missing short value", args: []string{"-o"}, wantAnyErr: true},
+		{name: "missing long value", args: []string{"--output"}, wantAnyErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := getTestRootCommand()
+			rest, err := parseRootFlags(rootCmd, tc.args)
+			if tc.wantErr != nil || tc.wantAnyErr {
+				if err == nil {
+					t.Fatalf("expected an error, got the args %v", rest)
+				}
+				if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+					t.Fatalf("expected the error %q, got %q", tc.wantErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %q", err)
+				}
+				if !reflect.DeepEqual(rest, tc.wantRest) {
+					t.Fatalf("expected the args %v, got %v", tc.wantRest, rest)
+				}
+			}
+			flags := rootCmd.PersistentFlags()
+			if output, _ := flags.GetString("output"); output != tc.output {
+				t.Errorf("expected the output '%s', got '%s'", tc.output, output)
+			}
+			logLevel := tc.logLevel
+			if logLevel == "" {
+				logLevel = "info"
+			}
+			if got, _ := flags.GetString("log-level"); got != logLevel {
+				t.Errorf("expected the log level '%s', got '%s'", logLevel, got)
+			}
+			if verbose, _ := flags.GetBool("verbose"); verbose != tc.verbose {
+				t.Errorf("expected verbose to be %t, got %t", tc.verbose, verbose)
+			}
+		})
+	}
+}
+
+func TestParseRootFlagsWithRootCommand(t *testing.T) {
+	rootCmd := GetRootCommand()
+	rest, err := parseRootFlags(rootCmd, []string{"-ojson", "--log-level", "debug", "foo", "bar"})
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if !reflect.DeepEqual(rest, []string{"foo", "bar"}) {
+		t.Fatalf("expected the args [foo bar], got %v", rest)
+	}
+	if output, _ := rootCmd.PersistentFlags().GetString("output"); output != "json" {
+		t.Fatalf("expected the output 'json', got '%s'", output)
+	}
+	for _, args := range [][]string{{"-help"}, {"-h"}, {"--help"}} {
+		rootCmd := GetRootCommand()
+		// Cobra adds the help flag once the command is executed.
+		rootCmd.PersistentFlags().BoolP("help", "h", false, "help for konveyor")
+		if _, err := parseRootFlags(rootCmd, args); !errors.Is(err, errHelpFlag) {
+			t.Fatalf("expected the help error for %v, got %q", args, err)
+		}
+	}
+}
+
+func TestGetKonveyorCommands(t *testing.T) {
+	names := getKonveyorCommands(GetRootCommand())
+	for _, name := range []string{"help", "completion", "plugin", "alias", "history", "stats", "version"} {
+		if !common.Contains(name, names) {
+			t.Fatalf("expected the built-in command '%s' in %v", name, names)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// PLUGIN_COMPLETION_TIMEOUT is the maximum time a plugin is given to return its completions.
+	PLUGIN_COMPLETION_TIMEOUT = 5 * time.Second
+)
+
+// getPluginCompletions asks the plugin for completions using the Cobra completion protocol.
+// The plugin is run as "konveyor-<name> __complete <args>... <toComplete>" and is expected to print one
+// completion per line followed by a line of the form ":<directive>".
+// Plugins that don't follow the protocol get the default (file) completion.
+func getPluginCompletions(p types.DiscoveredPlugin, args []string, toComplete string, environment []string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), PLUGIN_COMPLETION_TIMEOUT)
+	defer cancel()
+	completeArgs := append([]string{cobra.ShellCompRequestCmd}, args...)
+	completeArgs = append(completeArgs, toComplete)
+	executable, completeArgs, err := plugin.GetPluginCommand(p, completeArgs, environment, false)
+	if err != nil {
+		logrus.Debugf("failed to get the command to run the plugin '%s'. Error: %q", p.Name, err)
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	completeCmd := plugin.CommandContext(ctx, executable, completeArgs...)
+	completeCmd.Env = environment
+	stdout := bytes.Buffer{}
+	completeCmd.Stdout = &stdout
+	if err := completeCmd.Run(); err != nil {
+		logrus.Debugf("failed to get the completions from the plugin '%s'. Error: %q", p.Name, err)
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	lines := strings.Split(strings.TrimRight(stdout.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, ":") {
+		logrus.Debugf("the plugin '%s' does not support completion", p.Name)
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	directive, err := strconv.Atoi(last[1:])
+	if err != nil {
+		logrus.Debugf("the plugin '%s' returned an invalid completion directive '%s'. Error: %q", p.Name, last, err)
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	completions := []string{}
+	for _, line := range lines[:len(lines)-1] {
+		if line != "" {
+			completions = append(completions, line)
+		}
+	}
+	return completions, cobra.ShellCompDirective(directive)
+}
+
+// lookupDescribedFlag returns the flag that matches the arg. Example: --source, -s, --source=src
+func lookupDescribedFlag(flags []types.FlagDescription, arg string) (types.FlagDescription, bool) {
+	name, _, _ := strings.Cut(arg, "=")
+	for _, flag := range flags {
+		if name == "--"+flag.Name || (flag.Shorthand != "" && name == "-"+flag.Shorthand) {
+			return flag, true
+		}
+	}
+	return types.FlagDescription{}, false
+}
+
+// findDescribedCommand follows the args through the commands described by the plugin.
+// It returns the last command found, the flags it accepts and the positional args after it.
+func findDescribedCommand(spec types.CommandDescription, args []string) (types.CommandDescription, []types.FlagDescription, []string) {
+	command := spec
+	inherited := []types.FlagDescription{}
+	flags := spec.Flags
+	positional := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			// Skip the value of the flag if it is the next arg.
+			if flag, ok := lookupDescribedFlag(flags, arg); ok && flag.Type != "bool" && !strings.Contains(arg, "=") {
+				i++
+			}
+			continue
+		}
+		if len(positional) == 0 {
+			idx := common.FindIndex(func(c types.CommandDescription) bool {
+				return c.Name == arg || common.Contains(arg, c.Aliases)
+			}, command.Commands)
+			if idx != -1 {
+				inherited = append(inherited, common.Filter(func(f types.FlagDescription) bool { return f.Persistent }, command.Flags)...)
+				command = command.Commands[idx]
+				flags = append(append([]types.FlagDescription{}, inherited...), command.Flags...)
+				continue
+			}
+		}
+		positional = append(positional, arg)
+	}
+	return command, flags, positional
+}
+
+// completeDescribedValues returns the values that start with toComplete. Files are completed if there are no values.
+func completeDescribedValues(values []string, toComplete, prefix string) ([]string, cobra.ShellCompDirective) {
+	if len(values) == 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	completions := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			completions = append(completions, prefix+value)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// getDescribedCompletions returns the completions using the commands described by the plugin, without running it.
+// Cobra already completes the names of the flags and, when commandsListed is true, the names of the subcommands
+// since they are added to the stub commands of the plugin.
+func getDescribedCompletions(spec types.CommandDescription, args []string, toComplete string, commandsListed bool) ([]string, cobra.ShellCompDirective) {
+	command, flags, positional := findDescribedCommand(spec, args)
+	if len(args) > 0 {
+		if prev := args[len(args)-1]; strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") {
+			if flag, ok := lookupDescribedFlag(flags, prev); ok && flag.Type != "bool" {
+				return completeDescribedValues(flag.Values, toComplete, "")
+			}
+		}
+	}
+	if strings.HasPrefix(toComplete, "-") {
+		if name, value, ok := strings.Cut(toComplete, "="); ok {
+			if flag, found := lookupDescribedFlag(flags, name); found && flag.Type != "bool" {
+				return completeDescribedValues(flag.Values, value, name+"=")
+			}
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions := []string{}
+	if len(positional) == 0 && !commandsListed {
+		for _, subCommand := range command.Commands {
+			if strings.HasPrefix(subCommand.Name, toComplete) {
+				completions = append(completions, subCommand.Name+"\t"+subCommand.Short)
+			}
+		}
+	}
+	for _, arg := range command.Args {
+		if strings.HasPrefix(arg, toComplete) {
+			completions = append(completions, arg)
+		}
+	}
+	if len(command.Args) == 0 && (len(command.Commands) == 0 || len(positional) > 0) {
+		return completions, cobra.ShellCompDirectiveDefault
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,232 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/cli/lib/config"
+	"github.com/konveyor/cli/lib/github"
+	"github.com/konveyor/cli/lib/history"
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/stats"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// rootFlagKeys maps the persistent flags of the root command to their config keys.
+var rootFlagKeys = map[string]string{
+	"log-level": "logLevel",
+	"output":    "output",
+}
+
+// applyConfig passes the resolved settings to the packages that use them.
+func applyConfig() {
+	repo := types.PluginRepoConfig{}
+	repo.Owner, _ = config.Resolve("pluginRepo.owner")
+	repo.Name, _ = config.Resolve("pluginRepo.name")
+	repo.Branch, _ = config.Resolve("pluginRepo.branch")
+	github.SetPluginRepo(repo)
+	containerRuntime, _ := config.Resolve("containerRuntime")
+	plugin.SetContainerRuntime(containerRuntime)
+	sandboxOptions := types.SandboxOptions{}
+	if env, ok := config.Resolve("sandbox.env"); ok {
+		sandboxOptions.PassAllEnv = env == types.SANDBOX_ENV_ALL
+	}
+	if enabled, ok := config.Resolve("sandbox.enabled"); ok {
+		sandboxOptions.Enabled, _ = strconv.ParseBool(enabled)
+	}
+	plugin.SetSandboxOptions(sandboxOptions)
+	if enabled, ok := config.Resolve("history.enabled"); ok {
+		historyEnabled, _ := strconv.ParseBool(enabled)
+		history.SetEnabled(historyEnabled)
+	}
+	if enabled, ok := config.Resolve("stats.enabled"); ok {
+		statsEnabled, _ := strconv.ParseBool(enabled)
+		stats.SetEnabled(statsEnabled)
+	}
+}
+
+// applyConfigToFlags sets the flags that were not specified on the command line
+// using the environment variables and the config file, in that order.
+func applyConfigToFlags(cmd *cobra.Command) {
+	cmdPath := strings.Fields(cmd.CommandPath())[1:]
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			return
+		}
+		key, ok := rootFlagKeys[f.Name]
+		if !ok || cmd.Root().PersistentFlags().Lookup(f.Name) != f {
+			key = config.GetFlagKey(cmdPath, f.Name)
+		}
+		value, ok := config.Resolve(key)
+		if !ok {
+			return
+		}
+		if err := f.Value.Set(value); err != nil {
+			logrus.Warnf("the value '%s' for the key '%s' is invalid for the flag '--%s'. Ignoring. Error: %q", value, key, f.Name, err)
+		}
+	})
+}
+
+// validateFlagKey checks that the key refers to an existing flag of an existing command.
+func validateFlagKey(rootCmd *cobra.Command, key string) error {
+	parts := strings.Split(strings.TrimPrefix(key, config.FLAGS_KEY_PREFIX), ".")
+	cmdPath, flagName := parts[:len(parts)-1], parts[len(parts)-1]
+	cmd := rootCmd
+	if len(cmdPath) > 0 {
+		c, rest, err := rootCmd.Find(cmdPath)
+		if err != nil || len(rest) > 0 || c == rootCmd {
+			return fmt.Errorf("there is no command named '%s'", strings.Join(cmdPath, " "))
+		}
+		cmd = c
+	}
+	if cmd.Flags().Lookup(flagName) == nil && cmd.PersistentFlags().Lookup(flagName) == nil {
+		return fmt.Errorf("the command '%s' has no flag named '%s'", cmd.CommandPath(), flagName)
+	}
+	return nil
+}
+
+// GetConfigCommand returns the config command
+func GetConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and edit the configuration file.",
+		Long: `View and edit the configuration file.
+
+    The configuration file is stored at ` + config.GetConfigPath() + `
+    It can be moved elsewhere using the ` + types.ENV_CONFIG + ` environment variable.
+
+    Valid keys are: ` + strings.Join(config.GetSettingKeys(), ", ") + `
+    The default values of command flags can be set using keys of the form ` + config.FLAGS_KEY_PREFIX + `<command>.<subcommand>.<flag>
+    Example: ` + config.FLAGS_KEY_PREFIX + `plugin.list.name-only
+
+    Every key can also be set using an environment variable. Example: ` + config.GetEnvName("logLevel") + `, ` + config.GetEnvName(config.FLAGS_KEY_PREFIX+"plugin.list.name-only") + `
+    Values are resolved in the order: flag > environment variable > config file > default.
+`,
+	}
+	configCmd.AddCommand(GetConfigViewSubCommand())
+	configCmd.AddCommand(GetConfigGetSubCommand())
+	configCmd.AddCommand(GetConfigSetSubCommand())
+	configCmd.AddCommand(GetConfigUnsetSubCommand())
+	return configCmd
+}
+
+// GetConfigViewSubCommand returns a command to display the config file.
+func GetConfigViewSubCommand() *cobra.Command {
+	configViewCmd := &cobra.Command{
+		Use:   "view",
+		Args:  cobra.NoArgs,
+		Short: "Display the configuration file.",
+		Long:  "Display the configuration file.",
+		Run: func(*cobra.Command, []string) {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				logrus.Fatalf("failed to get the config. Error: %q", err)
+			}
+			cfgYaml, err := yaml.Marshal(cfg)
+			if err != nil {
+				logrus.Fatalf("failed to marshal the config to yaml. Error: %q", err)
+			}
+			fmt.Print(string(cfgYaml))
+		},
+	}
+	return configViewCmd
+}
+
+// GetConfigGetSubCommand returns a command to display the value of a key in the config file.
+func GetConfigGetSubCommand() *cobra.Command {
+	configGetCmd := &cobra.Command{
+		Use:   "get <key>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Display the value of a key in the configuration file.",
+		Long:  "Display the value of a key in the configuration file.",
+		Run: func(_ *cobra.Command, args []string) {
+			key := args[0]
+			cfg, err := config.GetConfig()
+			if err != nil {
+				logrus.Fatalf("failed to get the config. Error: %q", err)
+			}
+			value, err := config.Get(cfg, key)
+			if err != nil {
+				logrus.Fatalf("failed to get the value of the key '%s'. Error: %q", key, err)
+			}
+			fmt.Println(value)
+		},
+	}
+	return configGetCmd
+}
+
+// GetConfigSetSubCommand returns a command to set the value of a key in the config file.
+func GetConfigSetSubCommand() *cobra.Command {
+	configSetCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Set the value of a key in the configuration file.",
+		Long:  "Set the value of a key in the configuration file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			key, value := args[0], args[1]
+			if config.IsFlagKey(key) {
+				if err := validateFlagKey(cmd.Root(), key); err != nil {
+					logrus.Fatalf("the key '%s' is invalid. Error: %q", key, err)
+				}
+			}
+			cfg, err := config.GetConfig()
+			if err != nil {
+				logrus.Fatalf("failed to get the config. Error: %q", err)
+			}
+			if err := config.Set(&cfg, key, value); err != nil {
+				logrus.Fatalf("failed to set the value of the key '%s'. Error: %q", key, err)
+			}
+			if err := config.SaveConfig(cfg); err != nil {
+				logrus.Fatalf("failed to save the config. Error: %q", err)
+			}
+			logrus.Infof("The key '%s' was set to '%s'", key, value)
+		},
+	}
+	return configSetCmd
+}
+
+// GetConfigUnsetSubCommand returns a command to remove a key from the config file.
+func GetConfigUnsetSubCommand() *cobra.Command {
+	configUnsetCmd := &cobra.Command{
+		Use:   "unset <key>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Remove a key from the configuration file.",
+		Long:  "Remove a key from the configuration file.",
+		Run: func(_ *cobra.Command, args []string) {
+			key := args[0]
+			cfg, err := config.GetConfig()
+			if err != nil {
+				logrus.Fatalf("failed to get the config. Error: %q", err)
+			}
+			if err := config.Unset(&cfg, key); err != nil {
+				logrus.Fatalf("failed to unset the key '%s'. Error: %q", key, err)
+			}
+			if err := config.SaveConfig(cfg); err != nil {
+				logrus.Fatalf("failed to save the config. Error: %q", err)
+			}
+			logrus.Infof("The key '%s' was unset", key)
+		},
+	}
+	return configUnsetCmd
+}
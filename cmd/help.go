@@ -0,0 +1,275 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// PLUGIN_COMMAND_ANNOTATION marks the commands that stand in for plugins.
+	PLUGIN_COMMAND_ANNOTATION = "cli.konveyor.io/plugin"
+)
+
+// usageTemplate is the default Cobra usage template with an extra section for the plugin commands.
+const usageTemplate = `Usage:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} [command]{{end}}{{if gt (len .Aliases) 0}}
+
+Aliases:
+  {{.NameAndAliases}}{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{if hasBuiltinCommands .}}
+
+Available Commands:{{range .Commands}}{{if (and (not (isPluginCommand .)) (or .IsAvailableCommand (eq .Name "help")))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if hasPluginCommands .}}
+
+Plugin Commands:{{range .Commands}}{{if (and (isPluginCommand .) .IsAvailableCommand)}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+
+Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
+  {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+`
+
+func init() {
+	cobra.AddTemplateFunc("isPluginCommand", isPluginCommand)
+	cobra.AddTemplateFunc("hasPluginCommands", func(cmd *cobra.Command) bool {
+		for _, c := range cmd.Commands() {
+			if isPluginCommand(c) {
+				return true
+			}
+		}
+		return false
+	})
+	cobra.AddTemplateFunc("hasBuiltinCommands", func(cmd *cobra.Command) bool {
+		for _, c := range cmd.Commands() {
+			if !isPluginCommand(c) && (c.IsAvailableCommand() || c.Name() == "help") {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func isPluginCommand(cmd *cobra.Command) bool {
+	_, ok := cmd.Annotations[PLUGIN_COMMAND_ANNOTATION]
+	return ok
+}
+
+// addPluginCommands adds a command to the root command for each plugin that can be run.
+// Nested plugins are added as subcommands, creating the intermediate commands if necessary.
+func addPluginCommands(rootCmd *cobra.Command) {
+	plugins, err := plugin.GetPlugins()
+	if err != nil {
+		logrus.Debugf("failed to get the list of plugins. Error: %q", err)
+		return
+	}
+	// Add the parents before the children.
+	sort.SliceStable(plugins, func(i, j int) bool {
+		return len(strings.Fields(plugins[i].Name)) < len(strings.Fields(plugins[j].Name))
+	})
+	for _, p := range plugins {
+		parts := strings.Fields(p.Name)
+		parentCmd := rootCmd
+		for i, part := range parts[:len(parts)-1] {
+			parentCmd = getOrAddPluginGroupCommand(parentCmd, part, strings.Join(parts[:i+1], " "))
+		}
+		// A nested plugin takes precedence over a command with the same name described by its parent plugin.
+		for _, c := range parentCmd.Commands() {
+			if c.Name() == parts[len(parts)-1] && !isPluginCommand(c) {
+				parentCmd.RemoveCommand(c)
+			}
+		}
+		parentCmd.AddCommand(getPluginStubCommand(rootCmd, p))
+	}
+}
+
+// getOrAddPluginGroupCommand returns the subcommand with the given name, adding a command
+// that only groups the nested plugins if there is no such subcommand.
+func getOrAddPluginGroupCommand(parentCmd *cobra.Command, name, fullName string) *cobra.Command {
+	for _, c := range parentCmd.Commands() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	groupCmd := &cobra.Command{
+		Use:         name,
+		Short:       "Plugins under \"" + fullName + "\"",
+		Annotations: map[string]string{PLUGIN_COMMAND_ANNOTATION: ""},
+	}
+	parentCmd.AddCommand(groupCmd)
+	return groupCmd
+}
+
+// getPluginStubCommand returns a command that stands in for the plugin.
+// All the args are passed through to the plugin as is.
+// If the plugin describes its commands they are added as subcommands so that they show up in the help.
+func getPluginStubCommand(rootCmd *cobra.Command, p types.DiscoveredPlugin) *cobra.Command {
+	parts := strings.Fields(p.Name)
+	description, err := plugin.GetPluginDescription(p)
+	described := err == nil
+	if err != nil && !errors.Is(err, types.ErrNoPluginDescription) {
+		logrus.Debugf("failed to get the description of the plugin '%s'. Error: %q", p.Name, err)
+	}
+	short := "Run the " + p.Name + " plugin"
+	if described && description.Spec.Short != "" {
+		short = description.Spec.Short
+	}
+	if p.Installed != nil {
+		if pluginMeta, err := plugin.GetPluginMetadataFromLocalCache(p.Name); err == nil && pluginMeta.Spec.ShortDescription != "" {
+			short = pluginMeta.Spec.ShortDescription
+		}
+	}
+	stubCmd := &cobra.Command{
+		Use:                parts[len(parts)-1],
+		Short:              short,
+		Annotations:        map[string]string{PLUGIN_COMMAND_ANNOTATION: p.Path},
+		DisableFlagParsing: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if described {
+				return getDescribedCompletions(description.Spec, args, toComplete, len(args) == 0)
+			}
+			return getPluginCompletions(p, args, toComplete, getPluginEnvironment(cmd.Root(), p))
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executePluginStub(cmd, p, args)
+		},
+	}
+	if described {
+		// The help is shown using the description instead of running the plugin.
+		stubCmd.Long = description.Spec.Long
+		reserved := map[string]bool{"--help": true, "-h": true}
+		rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+			reserved["--"+f.Name] = true
+			reserved["-"+f.Shorthand] = f.Shorthand != ""
+		})
+		addDescribedCommand(stubCmd, p, nil, description.Spec, description.Spec, reserved)
+		return stubCmd
+	}
+	// "konveyor help <plugin>" shows the plugin's own help.
+	stubCmd.SetHelpFunc(func(cmd *cobra.Command, _ []string) {
+		if err := executePluginStub(cmd, p, []string{"--help"}); err != nil {
+			var exitErr *types.PluginExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.Code)
+			}
+			logrus.Fatalf("failed to get the help for the plugin '%s'. Error: %q", p.Name, err)
+		}
+	})
+	return stubCmd
+}
+
+// executePluginStub runs the plugin of a stub command with the given args the same way as running it directly.
+func executePluginStub(cmd *cobra.Command, p types.DiscoveredPlugin, args []string) error {
+	// The plugin reports its own errors and usage, and the others are reported by main.
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return runPlugin(cmd.Root(), p, args)
+}
+
+// describedFlagValue holds the value of a flag described by a plugin. It is only used to show the flag in the help.
+type describedFlagValue struct {
+	typ   string
+	value string
+}
+
+func (v *describedFlagValue) String() string { return v.value }
+
+func (v *describedFlagValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *describedFlagValue) Type() string { return v.typ }
+
+// addDescribedCommand adds the flags and the subcommands described by the plugin to the command.
+// The path is the list of subcommands leading to the command and spec describes the whole plugin.
+// Reserved contains the flag names (--name) and shorthands (-s) that are already used by the parent commands.
+func addDescribedCommand(cmd *cobra.Command, p types.DiscoveredPlugin, path []string, spec, command types.CommandDescription, reserved map[string]bool) {
+	inherited := map[string]bool{}
+	for k, v := range reserved {
+		inherited[k] = v
+	}
+	local := map[string]bool{}
+	for _, flag := range command.Flags {
+		used := local
+		if flag.Persistent {
+			used = inherited
+		}
+		if inherited["--"+flag.Name] || local["--"+flag.Name] {
+			logrus.Debugf("The flag --%s described by the plugin '%s' collides with another flag. Skipping.", flag.Name, p.Name)
+			continue
+		}
+		shorthand := flag.Shorthand
+		if inherited["-"+shorthand] || local["-"+shorthand] || len(shorthand) != 1 {
+			shorthand = ""
+		}
+		typ := flag.Type
+		if typ == "" {
+			typ = "string"
+		}
+		flagSet := cmd.Flags()
+		if flag.Persistent {
+			flagSet = cmd.PersistentFlags()
+		}
+		f := flagSet.VarPF(&describedFlagValue{typ: typ, value: flag.Default}, flag.Name, shorthand, flag.Usage)
+		if typ == "bool" {
+			f.NoOptDefVal = "true"
+		}
+		used["--"+flag.Name] = true
+		used["-"+shorthand] = shorthand != ""
+	}
+	for _, subCommand := range command.Commands {
+		if subCommand.Name == "" {
+			continue
+		}
+		subPath := append(append([]string{}, path...), subCommand.Name)
+		subCmd := &cobra.Command{
+			Use:                subCommand.Name,
+			Aliases:            subCommand.Aliases,
+			Short:              subCommand.Short,
+			Long:               subCommand.Long,
+			DisableFlagParsing: true,
+			ValidArgsFunction: func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return getDescribedCompletions(spec, append(append([]string{}, subPath...), args...), toComplete, len(args) == 0)
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return executePluginStub(cmd, p, append(append([]string{}, subPath...), args...))
+			},
+		}
+		addDescribedCommand(subCmd, p, subPath, spec, subCommand, inherited)
+		cmd.AddCommand(subCmd)
+	}
+}
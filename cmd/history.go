@@ -0,0 +1,116 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/history"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// parseSince parses either a duration before now (Example: 7d, 12h) or a date (Example: 2022-10-01).
+func parseSince(since string) (time.Time, error) {
+	if d, err := common.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, since, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("'%s' is neither a duration nor a date. Example: 7d, 12h, 2022-10-01", since)
+}
+
+// formatHistory formats the entries of the history as a table.
+func formatHistory(entries []types.HistoryEntry) string {
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tPLUGIN\tVERSION\tEXIT\tDURATION\tCWD\tARGS")
+	for _, e := range entries {
+		duration := (time.Duration(e.DurationMs) * time.Millisecond).Round(time.Millisecond)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", e.Timestamp.Local().Format("2006-01-02 15:04:05"), e.Plugin, e.Version, e.ExitCode, duration, e.Cwd, strings.Join(e.Args, " "))
+	}
+	tw.Flush()
+	return w.String()
+}
+
+// GetHistoryCommand returns a command to show the runs of the plugins.
+func GetHistoryCommand() *cobra.Command {
+	filter := types.HistoryFilter{}
+	since := ""
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Args:  cobra.NoArgs,
+		Short: "Show the runs of the plugins",
+		Long: `Show the runs of the plugins
+
+    Every run of a plugin is recorded with the time, the plugin version and path, the args, the working directory,
+    the exit code and the duration. Args that look like secrets are redacted before they are recorded.
+    The history is stored in the storage directory and rotated once it gets too big.
+    Use "konveyor config set history.enabled false" to stop recording.
+
+    Example: konveyor history --plugin move2kube --since 7d --cwd .
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if since != "" {
+				t, err := parseSince(since)
+				if err != nil {
+					logrus.Fatalf("the value of --since is invalid. Error: %q", err)
+				}
+				filter.Since = t
+			}
+			if filter.Cwd != "" {
+				cwd, err := filepath.Abs(filter.Cwd)
+				if err != nil {
+					logrus.Fatalf("failed to make the path %s absolute. Error: %q", filter.Cwd, err)
+				}
+				filter.Cwd = cwd
+			}
+			entries, err := history.GetEntries(filter)
+			if err != nil {
+				logrus.Fatalf("failed to read the history. Error: %q", err)
+			}
+			if output := getOutputFormat(cmd); output != "" {
+				outputStr, err := formatOutput(output, entries)
+				if err != nil {
+					logrus.Fatalf("failed to format the history. Error: %q", err)
+				}
+				fmt.Print(outputStr)
+				return
+			}
+			if len(entries) == 0 {
+				logrus.Infof("No runs were found in the history.")
+				return
+			}
+			fmt.Print(formatHistory(entries))
+		},
+	}
+	historyCmd.Flags().StringVar(&filter.Plugin, "plugin", "", "Only show the runs of this plugin")
+	historyCmd.Flags().StringVar(&since, "since", "", "Only show the runs since this duration ago or this date. Example: 7d, 12h, 2022-10-01")
+	historyCmd.Flags().StringVar(&filter.Cwd, "cwd", "", "Only show the runs in this directory or the directories under it")
+	historyCmd.Flags().BoolVar(&filter.Failed, "failed", false, "Only show the runs that exited with a non-zero exit code")
+	historyCmd.Flags().IntVarP(&filter.Limit, "limit", "n", 20, "Only show this many of the most recent runs. Use 0 to show all of them")
+	return historyCmd
+}
@@ -0,0 +1,51 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/konveyor/cli/lib/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// getOutputFormat returns the output format selected using the --output flag, empty for the default human readable output.
+func getOutputFormat(cmd *cobra.Command) string {
+	output, _ := cmd.Flags().GetString("output")
+	return output
+}
+
+// formatOutput marshals the value using the output format.
+func formatOutput(output string, v interface{}) (string, error) {
+	switch output {
+	case types.OUTPUT_FORMAT_JSON:
+		outputJson, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal the output to json. Error: %w", err)
+		}
+		return string(outputJson) + "\n", nil
+	case types.OUTPUT_FORMAT_YAML:
+		outputYaml, err := yaml.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal the output to yaml. Error: %w", err)
+		}
+		return string(outputYaml), nil
+	}
+	return "", fmt.Errorf("the output format '%s' is not supported", output)
+}
@@ -19,14 +19,22 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/konveyor/cli/lib/common"
 	"github.com/konveyor/cli/lib/github"
 	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/stats"
 	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // GetPluginCommand returns the plugin command
@@ -43,8 +51,14 @@ func GetPluginCommand() *cobra.Command {
 	pluginCmd.AddCommand(GetPluginListSubCommand())
 	pluginCmd.AddCommand(GetPluginInstallCommand())
 	pluginCmd.AddCommand(GetPluginUninstallCommand())
+	pluginCmd.AddCommand(GetPluginLinkCommand())
+	pluginCmd.AddCommand(GetPluginUnlinkCommand())
 	pluginCmd.AddCommand(GetPluginTidyCommand())
 	pluginCmd.AddCommand(GetPluginInfoCommand())
+	pluginCmd.AddCommand(GetPluginCheckCommand())
+	pluginCmd.AddCommand(GetPluginConfigCommand())
+	pluginCmd.AddCommand(GetPluginValidateCommand())
+	pluginCmd.AddCommand(GetPluginManifestCommand())
 	return pluginCmd
 }
 
@@ -52,6 +66,7 @@ func GetPluginCommand() *cobra.Command {
 func GetPluginListSubCommand() *cobra.Command {
 	nameOnly := false
 	remote := false
+	sortBy := types.PLUGIN_SORT_NAME
 	pluginListCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all the installed plugins.",
@@ -59,8 +74,16 @@ func GetPluginListSubCommand() *cobra.Command {
 
     Installed plugins are those that are: - executable - anywhere on the user's PATH - begin with "` + types.VALID_PLUGIN_FILENAME_PREFIX + `"
     Also includes any plugins in the ` + common.GetStorageDir() + ` directory
+
+    Plugins on the PATH can be nested: ` + types.VALID_PLUGIN_FILENAME_PREFIX + `foo-bar is run as "konveyor foo bar".
+    Use underscores for dashes within a command: ` + types.VALID_PLUGIN_FILENAME_PREFIX + `foo_bar is run as "konveyor foo-bar".
+
+    Use --sort last-used to list the most recently used plugins first, along with when they were last run.
 `,
 		Run: func(*cobra.Command, []string) {
+			if !common.Contains(sortBy, types.PLUGIN_SORT_ORDERS) {
+				logrus.Fatalf("the value of --sort must be one of: %s", strings.Join(types.PLUGIN_SORT_ORDERS, ", "))
+			}
 			if remote {
 				logrus.Infof("Fetching the list of plugins from Github.")
 			} else {
@@ -74,91 +97,415 @@ func GetPluginListSubCommand() *cobra.Command {
 				logrus.Infof("The following plugins are available on Github:\n%s", strings.Join(plugins, "\n"))
 				return
 			}
-			plugins, err := plugin.GetPluginsList(nameOnly)
+			plugins, err := plugin.GetPlugins()
 			if err != nil {
-				logrus.Fatalf("failed to get the list of plugins from the PATH. Error: %q", err)
+				logrus.Fatalf("failed to get the list of plugins. Error: %q", err)
 			}
 			if len(plugins) == 0 {
 				logrus.Info("No plugins were found.")
 				return
 			}
-			logrus.Infof("The following plugins are installed:\n%s", strings.Join(plugins, "\n"))
+			if sortBy == types.PLUGIN_SORT_LAST_USED {
+				pluginStats, err := stats.GetPluginStats()
+				if err != nil {
+					logrus.Fatalf("failed to get the usage statistics of the plugins. Error: %q", err)
+				}
+				logrus.Infof("The following plugins are installed:\n%s", formatPluginsByLastUsed(plugins, nameOnly, pluginStats))
+				return
+			}
+			logrus.Infof("The following plugins are installed:\n%s", formatPluginsList(plugins, nameOnly))
 		},
 	}
-	pluginListCmd.Flags().BoolVar(&nameOnly, "name-only", false, "If true, display only the binary name of each plugin, rather than its full path")
+	pluginListCmd.Flags().BoolVar(&nameOnly, "name-only", false, "If true, display only the command name of each plugin, rather than its full path")
 	pluginListCmd.Flags().BoolVar(&remote, "remote", false, "If true, display only the list of plugins in the Github repo")
+	pluginListCmd.Flags().StringVar(&sortBy, "sort", sortBy, "Order of the plugins. One of: "+strings.Join(types.PLUGIN_SORT_ORDERS, ", "))
 	return pluginListCmd
 }
 
+// formatPluginsList formats the plugins as a tree of commands, with the paths to the executables unless nameOnly is set.
+func formatPluginsList(plugins []types.DiscoveredPlugin, nameOnly bool) string {
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	if nameOnly {
+		return strings.Join(common.Apply(func(p types.DiscoveredPlugin) string { return p.Name }, plugins), "\n")
+	}
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	printed := map[string]bool{}
+	for _, p := range plugins {
+		parts := strings.Fields(p.Name)
+		for i := range parts[:len(parts)-1] {
+			parent := strings.Join(parts[:i+1], " ")
+			if !printed[parent] {
+				printed[parent] = true
+				fmt.Fprintf(tw, "%s%s\t\n", strings.Repeat("  ", i), parts[i])
+			}
+		}
+		printed[p.Name] = true
+		path := p.Path
+		if p.Installed != nil && p.Installed.Linked {
+			path += " (linked)"
+		} else if p.Installed != nil && p.Installed.Image != "" {
+			path = p.Installed.Image + " (image)"
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\n", strings.Repeat("  ", len(parts)-1), parts[len(parts)-1], path)
+	}
+	tw.Flush()
+	return strings.TrimSuffix(w.String(), "\n")
+}
+
+// formatLastUsed formats the time the plugin was last run.
+func formatLastUsed(s types.PluginStats) string {
+	if s.LastUsed.IsZero() {
+		return "never"
+	}
+	return s.LastUsed.Local().Format("2006-01-02 15:04:05")
+}
+
+// formatPluginsByLastUsed formats the plugins as a flat list with the most recently used first.
+func formatPluginsByLastUsed(plugins []types.DiscoveredPlugin, nameOnly bool, pluginStats map[string]types.PluginStats) string {
+	sort.SliceStable(plugins, func(i, j int) bool {
+		ti, tj := pluginStats[plugins[i].Name].LastUsed, pluginStats[plugins[j].Name].LastUsed
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	if nameOnly {
+		return strings.Join(common.Apply(func(p types.DiscoveredPlugin) string { return p.Name }, plugins), "\n")
+	}
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, p := range plugins {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Name, formatLastUsed(pluginStats[p.Name]), p.Path)
+	}
+	tw.Flush()
+	return strings.TrimSuffix(w.String(), "\n")
+}
+
 // GetPluginInstallCommand returns a command to install a plugin.
 func GetPluginInstallCommand() *cobra.Command {
+	archive := ""
+	name := ""
+	version := ""
+	bin := ""
+	checkSum := ""
+	hookOptions := types.HookOptions{}
 	pluginInstallCmd := &cobra.Command{
-		Use:   "install",
-		Args:  cobra.MinimumNArgs(1),
+		Use:   "install [name | path/to/plugin.yaml | directory | URL]",
+		Args:  cobra.MaximumNArgs(1),
 		Short: "Install a plugin",
-		Long:  "Install a plugin",
-		Run: func(_ *cobra.Command, args []string) {
-			name := args[0]
-			logrus.Infof("Looking for a plugin named '%s' on Github.", name)
-			if err := plugin.InstallPluginFromGithub(name); err != nil {
+		Long: `Install a plugin
+
+    The plugin can be specified using:
+    - the name of a plugin in the Github repo. Example: konveyor plugin install move2kube
+    - the path to a plugin YAML, or a directory containing one. Example: konveyor plugin install ./my-plugin.yaml
+    - the URL of a plugin YAML. Example: konveyor plugin install https://example.com/my-plugin.yaml
+    Relative uris in a plugin YAML are resolved relative to the location of the YAML.
+
+    A plugin archive (.tar.gz) or directory built for the current platform can be installed directly.
+    Example: konveyor plugin install --archive ./build/konveyor-foo.tar.gz --name foo --version dev
+
+    Plugins can declare a postInstall hook that is run after the plugin is extracted.
+    You are asked before it is run, use --yes to run it without asking or --no-hooks to skip it.
+
+    Plugins can request permissions such as network access and environment variables.
+    You are asked to grant them before the plugin is installed, use --yes to grant them without asking.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			plugin.SetHookOptions(hookOptions)
+			if archive != "" {
+				if len(args) > 0 {
+					logrus.Fatalf("a plugin name or YAML cannot be specified along with the --archive flag")
+				}
+				if name == "" || version == "" {
+					logrus.Fatalf("the --name and --version flags are required when installing from an archive")
+				}
+				logrus.Infof("Installing the plugin named '%s' from the archive at %s", name, archive)
+				if err := plugin.InstallPluginFromArchive(name, version, archive, bin, checkSum); err != nil {
+					if errors.Is(err, types.ErrPluginAlreadyInstalled) {
+						logrus.Fatal(err)
+					}
+					logrus.Fatalf("failed to install the plugin named '%s' from the archive at %s . Error: %q", name, archive, err)
+				}
+				logrus.Infof("The plugin named '%s' was installed!", name)
+				return
+			}
+			if len(args) == 0 {
+				cmd.Help()
+				logrus.Fatalf("specify a plugin name, YAML or URL to install")
+			}
+			source := args[0]
+			if plugin.IsPluginManifestSource(source) {
+				logrus.Infof("Installing the plugin using the plugin YAML at %s", source)
+				name, err := plugin.InstallPluginFromSource(source)
+				if err != nil {
+					if errors.Is(err, types.ErrPluginAlreadyInstalled) {
+						logrus.Fatalf("the plugin named '%s' is already installed", name)
+					}
+					logrus.Fatalf("failed to install the plugin using the plugin YAML at %s . Error: %q", source, err)
+				}
+				logrus.Infof("The plugin named '%s' was installed!", name)
+				return
+			}
+			logrus.Infof("Looking for a plugin named '%s' on Github.", source)
+			if err := plugin.InstallPluginFromGithub(source); err != nil {
 				if errors.Is(err, types.ErrPluginAlreadyInstalled) {
 					logrus.Fatal(err)
 				}
-				logrus.Fatalf("failed to find or install the plugin named '%s'. Error: %q", name, err)
+				logrus.Fatalf("failed to find or install the plugin named '%s'. Error: %q", source, err)
 			}
-			logrus.Infof("The plugin named '%s' was installed!", name)
+			logrus.Infof("The plugin named '%s' was installed!", source)
 		},
 	}
+	pluginInstallCmd.Flags().StringVar(&archive, "archive", "", "Path to a plugin archive (.tar.gz) or directory built for the current platform")
+	pluginInstallCmd.Flags().StringVar(&name, "name", "", "Name of the plugin being installed from an archive")
+	pluginInstallCmd.Flags().StringVar(&version, "version", "", "Version of the plugin being installed from an archive")
+	pluginInstallCmd.Flags().StringVar(&bin, "bin", "", "Path to the plugin executable inside the archive. Defaults to "+types.VALID_PLUGIN_FILENAME_PREFIX+"<name>")
+	pluginInstallCmd.Flags().StringVar(&checkSum, "sha256", "", "Expected sha256 checksum of the archive")
+	pluginInstallCmd.Flags().BoolVar(&hookOptions.Disabled, "no-hooks", false, "Skip the postInstall hook")
+	pluginInstallCmd.Flags().BoolVarP(&hookOptions.AssumeYes, "yes", "y", false, "Grant the permissions requested by the plugin and run the postInstall hook without asking for confirmation")
 	return pluginInstallCmd
 }
 
 // GetPluginUninstallCommand returns a command to uninstall a plugin.
 func GetPluginUninstallCommand() *cobra.Command {
+	hookOptions := types.HookOptions{}
+	removeImage := false
+	purge := false
 	pluginUninstallCmd := &cobra.Command{
 		Use:   "uninstall",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Uninstall a plugin",
-		Long:  "Uninstall a plugin",
+		Long: `Uninstall a plugin
+
+    Plugins can declare a preUninstall hook that is run before the plugin is removed.
+    You are asked before it is run, use --yes to run it without asking or --no-hooks to skip it.
+
+    The container image of a container image plugin is kept unless --remove-image is used.
+    The config and data directories of the plugin are kept for when it is installed again unless --purge is used.
+    Use --purge on a plugin that is no longer installed to remove the directories it left behind.
+`,
 		Run: func(_ *cobra.Command, args []string) {
+			plugin.SetHookOptions(hookOptions)
 			name := args[0]
 			logrus.Infof("Looking for a plugin named '%s' among the installed plugins.", name)
+			installed, err := plugin.GetPluginFromLocalCache(name)
+			if err != nil {
+				if purge && errors.Is(err, types.ErrPluginNotInstalled) {
+					purgePluginDirs(name)
+					return
+				}
+				logrus.Fatalf("failed to find the plugin named '%s'. Error: %q", name, err)
+			}
 			if err := plugin.UninstallPlugin(name); err != nil {
 				logrus.Fatalf("failed to find or uninstall the plugin named '%s'. Error: %q", name, err)
 			}
 			logrus.Infof("The plugin named '%s' was uninstalled!", name)
+			if removeImage && installed.Image != "" {
+				if err := plugin.RemoveImage(installed.Image); err != nil {
+					logrus.Fatalf("failed to remove the container image of the plugin named '%s'. Error: %q", name, err)
+				}
+			}
+			if purge {
+				purgePluginDirs(name)
+			}
 		},
 	}
+	pluginUninstallCmd.Flags().BoolVar(&removeImage, "remove-image", false, "Also remove the container image of a container image plugin")
+	pluginUninstallCmd.Flags().BoolVar(&purge, "purge", false, "Also remove the config and data directories of the plugin")
+	pluginUninstallCmd.Flags().BoolVar(&hookOptions.Disabled, "no-hooks", false, "Skip the preUninstall hook")
+	pluginUninstallCmd.Flags().BoolVarP(&hookOptions.AssumeYes, "yes", "y", false, "Run the preUninstall hook without asking for confirmation")
 	return pluginUninstallCmd
 }
 
+// purgePluginDirs removes the config and data directories of the plugin.
+func purgePluginDirs(name string) {
+	found, err := plugin.PurgePluginDirs(name)
+	if err != nil {
+		logrus.Fatalf("failed to remove the config and data of the plugin named '%s'. Error: %q", name, err)
+	}
+	if !found {
+		logrus.Infof("The plugin named '%s' has no config or data to remove.", name)
+		return
+	}
+	logrus.Infof("The config and data of the plugin named '%s' were removed.", name)
+}
+
+// GetPluginCheckCommand returns a command to verify that an installed plugin works.
+func GetPluginCheckCommand() *cobra.Command {
+	pluginCheckCmd := &cobra.Command{
+		Use:   "check <name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Verify that an installed plugin works",
+		Long: `Verify that an installed plugin works
+
+    Checks that the plugin executable exists and the requirements of the plugin are satisfied.
+    Then runs the check hook if the plugin declares one.
+`,
+		Run: func(_ *cobra.Command, args []string) {
+			name := args[0]
+			if err := plugin.CheckPlugin(name); err != nil {
+				logrus.Fatalf("the plugin named '%s' failed the check. Error: %q", name, err)
+			}
+			logrus.Infof("The plugin named '%s' passed the check!", name)
+		},
+	}
+	return pluginCheckCmd
+}
+
+// GetPluginLinkCommand returns a command to link a development plugin.
+func GetPluginLinkCommand() *cobra.Command {
+	version := ""
+	bin := ""
+	interpreter := ""
+	pluginLinkCmd := &cobra.Command{
+		Use:   "link <name> <path-to-binary-or-dir>",
+		Args:  cobra.ExactArgs(2),
+		Short: "Link a plugin that is being developed",
+		Long: `Link a plugin that is being developed
+
+    The plugin runs the executable at the given path, so rebuilding it takes effect immediately.
+    If the path is a directory, the executable inside it defaults to ` + types.VALID_PLUGIN_FILENAME_PREFIX + `<name>
+    Linked plugins can be removed using unlink or uninstall. The files at the path are never deleted.
+
+    Example: konveyor plugin link foo ./konveyor-foo.py --interpreter 'python3 >=3.8'
+`,
+		Run: func(_ *cobra.Command, args []string) {
+			name, path := args[0], args[1]
+			installed, err := plugin.LinkPlugin(name, version, path, bin, interpreter)
+			if err != nil {
+				if errors.Is(err, types.ErrPluginAlreadyInstalled) {
+					logrus.Fatal(err)
+				}
+				logrus.Fatalf("failed to link the plugin named '%s' to the path %s . Error: %q", name, path, err)
+			}
+			logrus.Infof("The plugin named '%s' was linked to %s", name, plugin.GetPluginBinPath(installed))
+		},
+	}
+	pluginLinkCmd.Flags().StringVar(&version, "version", "dev", "Version reported for the linked plugin")
+	pluginLinkCmd.Flags().StringVar(&bin, "bin", "", "Path to the plugin executable when linking a directory")
+	pluginLinkCmd.Flags().StringVar(&interpreter, "interpreter", "", "Interpreter that runs the plugin script, followed by an optional version constraint. Example: sh, 'python3 >=3.8'")
+	return pluginLinkCmd
+}
+
+// GetPluginUnlinkCommand returns a command to unlink a development plugin.
+func GetPluginUnlinkCommand() *cobra.Command {
+	pluginUnlinkCmd := &cobra.Command{
+		Use:   "unlink <name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Unlink a plugin that is being developed",
+		Long:  "Unlink a plugin that is being developed. The files it points at are not deleted.",
+		Run: func(_ *cobra.Command, args []string) {
+			name := args[0]
+			if err := plugin.UnlinkPlugin(name); err != nil {
+				logrus.Fatalf("failed to unlink the plugin named '%s'. Error: %q", name, err)
+			}
+			logrus.Infof("The plugin named '%s' was unlinked!", name)
+		},
+	}
+	return pluginUnlinkCmd
+}
+
 // GetPluginTidyCommand returns a command to tidy the plugins directory.
 func GetPluginTidyCommand() *cobra.Command {
+	unusedSince := ""
 	pluginTidyCmd := &cobra.Command{
 		Use:   "tidy",
 		Args:  cobra.NoArgs,
 		Short: "Cleans the plugins directory, removing any broken plugins to ensure consistency with the local cache",
-		Long:  "Cleans the plugins directory, removing any broken plugins to ensure consistency with the local cache",
+		Long: `Cleans the plugins directory, removing any broken plugins to ensure consistency with the local cache
+
+    Use --unused-since to also list the installed plugins that have not been run for that long. Example: 90d
+    They are only suggested for removal, uninstall them using "konveyor plugin uninstall <name>".
+`,
 		Run: func(*cobra.Command, []string) {
+			var unusedDuration time.Duration
+			if unusedSince != "" {
+				d, err := common.ParseDuration(unusedSince)
+				if err != nil {
+					logrus.Fatalf("the value of --unused-since is invalid. Error: %q", err)
+				}
+				unusedDuration = d
+			}
 			logrus.Infof("Looking for any inconsistencies between the local cache and the installed plugins.")
 			if err := plugin.UninstallBrokenPlugins(); err != nil {
 				logrus.Fatalf("failed to uninstall all the broken plugins. Error: %q", err)
 			}
+			if unusedSince != "" {
+				suggestUnusedPlugins(unusedSince, unusedDuration)
+			}
 			logrus.Infof("Tidying done!")
 		},
 	}
+	pluginTidyCmd.Flags().StringVar(&unusedSince, "unused-since", "", "Suggest removing the installed plugins that have not been run for this long. Example: 90d")
 	return pluginTidyCmd
 }
 
+// suggestUnusedPlugins lists the installed plugins that have not been run for the given duration.
+func suggestUnusedPlugins(unusedSince string, unusedDuration time.Duration) {
+	if !stats.IsEnabled() {
+		logrus.Warnf("The usage of the plugins is not being recorded, so some of the plugins below may still be in use. Use \"konveyor config set stats.enabled true\" to record it.")
+	}
+	unused, pluginStats, err := plugin.GetUnusedPlugins(time.Now().Add(-unusedDuration))
+	if err != nil {
+		logrus.Fatalf("failed to find the unused plugins. Error: %q", err)
+	}
+	if len(unused) == 0 {
+		logrus.Infof("All the installed plugins were used in the last %s.", unusedSince)
+		return
+	}
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, installed := range unused {
+		fmt.Fprintf(tw, "%s\tlast used: %s\n", installed.Name, formatLastUsed(pluginStats[installed.Name]))
+	}
+	tw.Flush()
+	logrus.Infof("The following plugins were not used in the last %s:\n%s", unusedSince, strings.TrimSuffix(w.String(), "\n"))
+	logrus.Infof("Use \"konveyor plugin uninstall <name>\" to remove the ones you no longer need.")
+}
+
 // GetPluginInfoCommand returns a command to display info about a plugin.
 func GetPluginInfoCommand() *cobra.Command {
+	commands := false
+	refresh := false
 	pluginInfoCmd := &cobra.Command{
 		Use:   "info",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Displays info about a plugin - available versions, links to homepage, documentation, etc.",
-		Long:  "Displays info about a plugin - available versions, links to homepage, documentation, etc.",
-		Run: func(_ *cobra.Command, args []string) {
+		Long: `Displays info about a plugin - available versions, links to homepage, documentation, etc.
+
+    Use --commands to show the commands and flags of an installed plugin. Plugins describe their commands
+    when they are run as "konveyor-<name> ` + types.PLUGIN_DESCRIBE_ARG + `". The description is cached when the plugin
+    is installed or linked and it is used for the help and the completion of the plugin.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
 			logrus.Debugf("plugin info called")
 			name := args[0]
+			if commands {
+				p, err := plugin.FindPlugin(name)
+				if err != nil {
+					logrus.Fatalf("failed to find a plugin named '%s'. Error: %q", name, err)
+				}
+				if refresh && p.Installed != nil {
+					if err := plugin.CachePluginDescription(p); err != nil {
+						logrus.Fatalf("failed to refresh the description of the plugin '%s'. Error: %q", name, err)
+					}
+				}
+				description, err := plugin.GetPluginDescription(p)
+				if err != nil {
+					logrus.Fatalf("failed to get the commands of the plugin '%s'. Error: %q", name, err)
+				}
+				if output := getOutputFormat(cmd); output != "" {
+					outputStr, err := formatOutput(output, description.Spec)
+					if err != nil {
+						logrus.Fatalf("failed to format the commands of the plugin. Error: %q", err)
+					}
+					fmt.Print(outputStr)
+					return
+				}
+				fmt.Print(plugin.FormatPluginCommands(name, description.Spec))
+				return
+			}
 			logrus.Infof("Looking for information on a plugin named '%s'", name)
 			info, err := plugin.GetPluginInfo(name)
 			if err != nil {
@@ -168,5 +515,279 @@ func GetPluginInfoCommand() *cobra.Command {
 			fmt.Println(info)
 		},
 	}
+	pluginInfoCmd.Flags().BoolVar(&commands, "commands", false, "Show the commands and flags described by the installed plugin")
+	pluginInfoCmd.Flags().BoolVar(&refresh, "refresh", false, "Ask the plugin to describe its commands again instead of using the cached description. Used with --commands")
 	return pluginInfoCmd
 }
+
+// GetPluginValidateCommand returns a command to check plugin YAMLs for problems.
+func GetPluginValidateCommand() *cobra.Command {
+	pluginValidateCmd := &cobra.Command{
+		Use:   "validate <file | directory>...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Check plugin YAMLs for problems",
+		Long: `Check plugin YAMLs for problems
+
+    Directories are searched for files ending in .yaml or .yml
+    Exits with a non-zero status if any errors are found. Warnings don't affect the exit status.
+    Use --output json or --output yaml for machine readable diagnostics.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			diagnostics := []types.Diagnostic{}
+			for _, path := range args {
+				ds, err := plugin.ValidatePluginPath(path)
+				if err != nil {
+					logrus.Fatalf("failed to validate the plugin YAMLs at path %s . Error: %q", path, err)
+				}
+				diagnostics = append(diagnostics, ds...)
+			}
+			if output := getOutputFormat(cmd); output != "" {
+				outputStr, err := formatOutput(output, diagnostics)
+				if err != nil {
+					logrus.Fatalf("failed to format the diagnostics. Error: %q", err)
+				}
+				fmt.Print(outputStr)
+			} else {
+				for _, d := range diagnostics {
+					fmt.Println(formatDiagnostic(d))
+				}
+			}
+			errs := common.Filter(func(d types.Diagnostic) bool { return d.Severity == types.SEVERITY_ERROR }, diagnostics)
+			if len(errs) > 0 {
+				logrus.Fatalf("found %d errors and %d warnings", len(errs), len(diagnostics)-len(errs))
+			}
+			logrus.Infof("found no errors and %d warnings", len(diagnostics))
+		},
+	}
+	return pluginValidateCmd
+}
+
+// formatDiagnostic formats the diagnostic in a human readable format. Example: plugins/foo.yaml:12: error: spec.name: message
+func formatDiagnostic(d types.Diagnostic) string {
+	location := d.File
+	if d.Line > 0 {
+		location += ":" + strconv.Itoa(d.Line)
+	}
+	if d.Path != "" {
+		return fmt.Sprintf("%s: %s: %s: %s", location, d.Severity, d.Path, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Message)
+}
+
+// GetPluginManifestCommand returns a command containing utilities for plugin authors to work with plugin YAMLs.
+func GetPluginManifestCommand() *cobra.Command {
+	pluginManifestCmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Utilities for plugin authors to work with plugin YAMLs",
+		Long:  "Utilities for plugin authors to work with plugin YAMLs",
+	}
+	pluginManifestCmd.AddCommand(GetPluginManifestGenerateSubCommand())
+	return pluginManifestCmd
+}
+
+// GetPluginManifestGenerateSubCommand returns a command to generate the metadata for a new version of a plugin from its release artifacts.
+func GetPluginManifestGenerateSubCommand() *cobra.Command {
+	repo := ""
+	tag := ""
+	dir := ""
+	assetPattern := ""
+	bin := ""
+	name := ""
+	manifest := ""
+	baseUrl := ""
+	pluginManifestGenerateCmd := &cobra.Command{
+		Use:   "generate",
+		Args:  cobra.NoArgs,
+		Short: "Generate the plugin YAML for a new version from its release artifacts",
+		Long: `Generate the plugin YAML for a new version from its release artifacts
+
+    The artifacts are either the assets of a Github release or the files in a local directory.
+    The os and arch of each artifact are inferred from its name using the asset pattern.
+    The pattern can contain the placeholders {{os}}, {{arch}}, {{version}} and {{name}}. A * matches anything.
+    The default pattern matches the archives created by "make dist". Example: move2kube-v0.3.5-linux-amd64.tar.gz
+    The checksums are read from the ` + types.CHECKSUM_FILE_SUFFIX + ` files if they exist, otherwise they are computed.
+
+    The bin can contain the placeholders {{os}}, {{arch}} and {{exe}} which is .exe on Windows.
+
+    If a plugin YAML is given using --manifest the new version is added to the top of its versions,
+    otherwise a new plugin YAML is printed.
+
+    Example: konveyor plugin manifest generate --repo konveyor/move2kube --tag v0.3.5 --bin move2kube/move2kube --manifest plugins/move2kube.yaml
+`,
+		Run: func(*cobra.Command, []string) {
+			if tag == "" || bin == "" {
+				logrus.Fatalf("the --tag and --bin flags are required")
+			}
+			owner, repoName := "", ""
+			if repo != "" {
+				var found bool
+				owner, repoName, found = strings.Cut(repo, "/")
+				if !found || owner == "" || repoName == "" {
+					logrus.Fatalf("the repo '%s' is invalid. Example: konveyor/move2kube", repo)
+				}
+			} else if dir == "" {
+				logrus.Fatalf("either --repo or --dir is required")
+			}
+			var manifestYaml []byte
+			if manifest != "" {
+				var err error
+				manifestYaml, err = os.ReadFile(manifest)
+				if err != nil {
+					logrus.Fatalf("failed to read the plugin YAML at path %s . Error: %q", manifest, err)
+				}
+				if name == "" {
+					pluginMeta := types.PluginMetadata{}
+					if err := yaml.Unmarshal(manifestYaml, &pluginMeta); err != nil {
+						logrus.Fatalf("failed to parse the plugin YAML at path %s . Error: %q", manifest, err)
+					}
+					name = pluginMeta.Metadata.Name
+				}
+			}
+			if name == "" {
+				name = repoName
+			}
+			if name == "" {
+				logrus.Fatalf("the --name flag is required")
+			}
+			var assets []types.ReleaseAsset
+			var err error
+			if dir != "" {
+				assets, err = plugin.GetLocalReleaseAssets(dir)
+				if err != nil {
+					logrus.Fatalf("failed to get the release artifacts. Error: %q", err)
+				}
+				if baseUrl == "" && repo != "" {
+					baseUrl = fmt.Sprintf("https://github.com/%s/%s/releases/download/%s", owner, repoName, tag)
+				}
+			} else {
+				logrus.Infof("Getting the assets of the release '%s' of the Github repo %s", tag, repo)
+				assets, err = github.GetReleaseAssets(owner, repoName, tag)
+				if err != nil {
+					logrus.Fatalf("failed to get the release assets. Error: %q", err)
+				}
+			}
+			version, err := plugin.GeneratePluginVersion(name, tag, assets, assetPattern, bin, baseUrl)
+			if err != nil {
+				logrus.Fatalf("failed to generate the plugin YAML. Error: %q", err)
+			}
+			if dir != "" && baseUrl == "" {
+				logrus.Warnf("The uris point at the local files. Use --base-url or --repo to point them at the published files.")
+				if manifestDir, err := filepath.Abs(filepath.Dir(manifest)); manifest != "" && err == nil {
+					for i, platform := range version.Platforms {
+						if relPath, err := filepath.Rel(manifestDir, platform.Uri); err == nil {
+							version.Platforms[i].Uri = relPath
+						}
+					}
+				}
+			}
+			if manifest == "" {
+				newYaml, err := plugin.NewPluginManifest(name, version)
+				if err != nil {
+					logrus.Fatalf("failed to generate the plugin YAML. Error: %q", err)
+				}
+				fmt.Print(string(newYaml))
+				return
+			}
+			newYaml, err := plugin.AddVersionToManifest(manifestYaml, version)
+			if err != nil {
+				logrus.Fatalf("failed to add the version '%s' to the plugin YAML at path %s . Error: %q", tag, manifest, err)
+			}
+			if err := os.WriteFile(manifest, newYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+				logrus.Fatalf("failed to write the plugin YAML to the path %s . Error: %q", manifest, err)
+			}
+			logrus.Infof("Added the version '%s' to the plugin YAML at path %s", tag, manifest)
+		},
+	}
+	pluginManifestGenerateCmd.Flags().StringVar(&repo, "repo", "", "Github repo containing the release. Example: konveyor/move2kube")
+	pluginManifestGenerateCmd.Flags().StringVar(&tag, "tag", "", "Tag of the release, used as the version of the plugin. Example: v0.3.5")
+	pluginManifestGenerateCmd.Flags().StringVar(&dir, "dir", "", "Local directory containing the release artifacts, instead of the Github release")
+	pluginManifestGenerateCmd.Flags().StringVar(&assetPattern, "asset-pattern", "*-{{os}}-{{arch}}.tar.gz", "Pattern matching the names of the release archives")
+	pluginManifestGenerateCmd.Flags().StringVar(&bin, "bin", "", "Path to the plugin executable inside the archives. Example: move2kube/move2kube")
+	pluginManifestGenerateCmd.Flags().StringVar(&name, "name", "", "Name of the plugin. Defaults to the name in the plugin YAML or the name of the repo")
+	pluginManifestGenerateCmd.Flags().StringVar(&manifest, "manifest", "", "Path to an existing plugin YAML to add the version to")
+	pluginManifestGenerateCmd.Flags().StringVar(&baseUrl, "base-url", "", "URL where the artifacts are published. Defaults to the Github release")
+	return pluginManifestGenerateCmd
+}
+
+// formatPluginConfig formats the config of a plugin as a table.
+func formatPluginConfig(config []types.PluginConfigValue) string {
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tDEFAULT\tDESCRIPTION")
+	for _, c := range config {
+		value := c.Value
+		if !c.IsSet {
+			value = "-"
+		}
+		description := c.Description
+		if len(c.Values) > 0 {
+			description = strings.TrimSpace(description + " (one of: " + strings.Join(c.Values, ", ") + ")")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Name, value, c.Default, description)
+	}
+	tw.Flush()
+	return w.String()
+}
+
+// GetPluginConfigCommand returns a command to view and edit the config of an installed plugin.
+func GetPluginConfigCommand() *cobra.Command {
+	pluginConfigCmd := &cobra.Command{
+		Use:   "config <name> get [<key>] | set <key> <value> | unset <key>",
+		Args:  cobra.RangeArgs(2, 4),
+		Short: "View and edit the config of an installed plugin",
+		Long: `View and edit the config of an installed plugin
+
+    Plugins declare the keys they can be configured with in their YAML. The values are stored in
+    ` + filepath.Join(common.GetStorageDir(), types.PLUGIN_CONFIG_DIR, "<name>", types.CONFIG_FILE) + `
+    which the plugin finds using the ` + types.ENV_PLUGIN_CONFIG_DIR + ` environment variable.
+    Keys that are not set use the default declared by the plugin.
+
+    Example: konveyor plugin config move2kube get
+    Example: konveyor plugin config move2kube set qa.skip true
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			name, action, rest := args[0], args[1], args[2:]
+			switch {
+			case action == "get" && len(rest) == 0:
+				config, err := plugin.GetPluginConfig(name)
+				if err != nil {
+					logrus.Fatalf("failed to get the config of the plugin named '%s'. Error: %q", name, err)
+				}
+				if output := getOutputFormat(cmd); output != "" {
+					outputStr, err := formatOutput(output, config)
+					if err != nil {
+						logrus.Fatalf("failed to format the config of the plugin. Error: %q", err)
+					}
+					fmt.Print(outputStr)
+					return
+				}
+				if len(config) == 0 {
+					logrus.Infof("The plugin named '%s' has no config keys.", name)
+					return
+				}
+				fmt.Print(formatPluginConfig(config))
+			case action == "get" && len(rest) == 1:
+				value, err := plugin.GetPluginConfigValue(name, rest[0])
+				if err != nil {
+					logrus.Fatalf("failed to get the value of the key '%s' for the plugin named '%s'. Error: %q", rest[0], name, err)
+				}
+				fmt.Println(value)
+			case action == "set" && len(rest) == 2:
+				key, value := rest[0], rest[1]
+				if err := plugin.SetPluginConfigValue(name, key, value); err != nil {
+					logrus.Fatalf("failed to set the value of the key '%s' for the plugin named '%s'. Error: %q", key, name, err)
+				}
+				logrus.Infof("The key '%s' of the plugin named '%s' was set to '%s'", key, name, value)
+			case action == "unset" && len(rest) == 1:
+				key := rest[0]
+				if err := plugin.UnsetPluginConfigValue(name, key); err != nil {
+					logrus.Fatalf("failed to unset the key '%s' for the plugin named '%s'. Error: %q", key, name, err)
+				}
+				logrus.Infof("The key '%s' of the plugin named '%s' was unset", key, name)
+			default:
+				logrus.Fatalf("invalid arguments. Usage: konveyor plugin %s", cmd.Use)
+			}
+		},
+	}
+	return pluginConfigCmd
+}
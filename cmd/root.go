@@ -17,6 +17,12 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/modfile"
@@ -25,6 +31,7 @@ import (
 // GetRootCommand returns the root command that contains all the other commands.
 func GetRootCommand() *cobra.Command {
 	loglevel := string(logrus.InfoLevel.String())
+	output := ""
 	rootCmd := &cobra.Command{
 		Use:   "konveyor",
 		Short: "Konveyor provides a suite of tools that help migrate apps running on legacy platforms to new ones.",
@@ -33,22 +40,50 @@ Each tool comes as a plugin that can be installed if necessary.
 
 Try "konveyor plugin --help" for more info about plugins and their installation.
 `,
-		PersistentPreRunE: func(*cobra.Command, []string) error {
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			applyConfigToFlags(cmd)
 			logl, err := logrus.ParseLevel(loglevel)
 			if err != nil {
 				logrus.Errorf("the log level '%s' is invalid, using 'info' log level instead. Error: %q", loglevel, err)
 				logl = logrus.InfoLevel
 			}
 			logrus.SetLevel(logl)
+			if output != "" && !common.Contains(output, types.OUTPUT_FORMATS) {
+				return fmt.Errorf("the output format '%s' is invalid. Valid formats are: %s", output, strings.Join(types.OUTPUT_FORMATS, ", "))
+			}
 			return nil
 		},
 	}
+	rootCmd.SetUsageTemplate(usageTemplate)
 	rootCmd.PersistentFlags().StringVar(&loglevel, "log-level", logrus.InfoLevel.String(), "Set logging levels.")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "Output format for commands that support it. One of: "+strings.Join(types.OUTPUT_FORMATS, ", "))
 	rootCmd.AddCommand(GetPluginCommand())
+	rootCmd.AddCommand(GetConfigCommand())
 	rootCmd.AddCommand(GetVersionCommand())
+	rootCmd.AddCommand(GetSelfUpdateCommand())
+	rootCmd.AddCommand(GetHistoryCommand())
+	rootCmd.AddCommand(GetStatsCommand())
+	rootCmd.AddCommand(GetAliasCommand())
+	setKonveyorCommands(rootCmd)
 	return rootCmd
 }
 
+// setKonveyorCommands passes the names of the built-in commands to the plugin package so that plugins and aliases can't hide them.
+func setKonveyorCommands(rootCmd *cobra.Command) {
+	plugin.SetKonveyorCommands(getKonveyorCommands(rootCmd))
+}
+
+// getKonveyorCommands returns the names and aliases of the sub-commands of the root command.
+func getKonveyorCommands(rootCmd *cobra.Command) []string {
+	// Cobra only adds the help and completion commands when the root command is executed.
+	names := []string{"help", "completion"}
+	for _, c := range rootCmd.Commands() {
+		names = append(names, c.Name())
+		names = append(names, c.Aliases...)
+	}
+	return names
+}
+
 // AvoidGoModWarnings does nothing but avoid warnings about unused packages in go.mod
 func AvoidGoModWarnings() {
 	// Just here because the scripts/detectgoversion/detect.go script uses `modfile`
@@ -0,0 +1,64 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/konveyor/cli/lib"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/update"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// GetSelfUpdateCommand returns a command to update konveyor
+func GetSelfUpdateCommand() *cobra.Command {
+	version := ""
+	selfUpdateCmd := &cobra.Command{
+		Use:   "self-update",
+		Args:  cobra.NoArgs,
+		Short: "Update konveyor to the latest or a specific version",
+		Long: `Update konveyor to the latest or a specific version
+
+    The release archive for the current platform is downloaded from https://github.com/` + update.RELEASE_REPO_OWNER + `/` + update.RELEASE_REPO_NAME + `/releases
+    Its checksum is verified before it replaces the running executable.
+`,
+		Run: func(*cobra.Command, []string) {
+			if version == "" {
+				logrus.Infof("Looking for the latest version of konveyor.")
+				latest, err := update.GetLatestVersion()
+				if err != nil {
+					logrus.Fatalf("failed to get the latest version of konveyor. Error: %q", err)
+				}
+				if !update.IsNewer(latest) {
+					logrus.Infof("konveyor is already at the latest version %s", lib.GetVersion())
+					return
+				}
+				version = latest
+			} else if common.NormalizeVersion(version) == lib.GetVersion() {
+				logrus.Infof("konveyor is already at the version %s", lib.GetVersion())
+				return
+			}
+			installed, err := update.SelfUpdate(version)
+			if err != nil {
+				logrus.Fatalf("failed to update konveyor to the version '%s'. Error: %q", version, err)
+			}
+			logrus.Infof("Updated konveyor from %s to %s", lib.GetVersion(), installed)
+		},
+	}
+	selfUpdateCmd.Flags().StringVar(&version, "version", "", "Version to update to. Defaults to the latest version")
+	return selfUpdateCmd
+}
@@ -0,0 +1,68 @@
+//go:build !windows
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// forwardedSignals are the signals that are passed on to a plugin running as a child process.
+var forwardedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// isForegroundProcessGroup returns true if konveyor is in the foreground process group of its terminal.
+func isForegroundProcessGroup() bool {
+	for _, f := range []*os.File{os.Stdin, os.Stdout, os.Stderr} {
+		if pgrp, err := unix.IoctlGetInt(int(f.Fd()), unix.TIOCGPGRP); err == nil {
+			return pgrp == unix.Getpgrp()
+		}
+	}
+	return false
+}
+
+// preparePluginProcess puts the plugin in a process group of its own so that the forwarded signals
+// also reach the processes it starts. When konveyor is in the foreground of a terminal the plugin
+// stays in our process group instead, so that it can read from the terminal and receives Ctrl-C directly.
+// It returns true if the plugin has a process group of its own.
+func preparePluginProcess(cmd *exec.Cmd) bool {
+	if isForegroundProcessGroup() {
+		return false
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	return true
+}
+
+// forwardSignal passes the signal received by konveyor on to the plugin.
+// A SIGINT is not sent again to a plugin in our process group since the terminal already sent it to the whole group.
+func forwardSignal(process *os.Process, sig os.Signal, ownGroup bool) {
+	if ownGroup {
+		_ = syscall.Kill(-process.Pid, sig.(syscall.Signal))
+		return
+	}
+	if sig == syscall.SIGINT {
+		return
+	}
+	_ = process.Signal(sig)
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// forwardedSignals are the signals that are passed on to a plugin running as a child process.
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// preparePluginProcess does nothing on Windows. The plugin shares the console of konveyor.
+func preparePluginProcess(_ *exec.Cmd) bool {
+	return false
+}
+
+// forwardSignal does nothing on Windows since Ctrl-C and Ctrl-Break are sent to every process attached to the console.
+// Konveyor only has to survive them so that it can wait for the plugin to exit.
+func forwardSignal(_ *os.Process, _ os.Signal, _ bool) {}
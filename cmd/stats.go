@@ -0,0 +1,84 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor/cli/lib/stats"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// formatStats formats the usage statistics of the plugins as a table.
+func formatStats(pluginStats []types.PluginStats) string {
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLUGIN\tRUNS\tLAST USED\tAVG DURATION")
+	for _, s := range pluginStats {
+		average := "-"
+		if s.TimedRuns > 0 {
+			average = s.AverageDuration().String()
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", s.Name, s.Runs, s.LastUsed.Local().Format("2006-01-02 15:04:05"), average)
+	}
+	tw.Flush()
+	return w.String()
+}
+
+// GetStatsCommand returns a command to show how often the plugins are used.
+func GetStatsCommand() *cobra.Command {
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Args:  cobra.NoArgs,
+		Short: "Show how often the plugins are used",
+		Long: `Show how often the plugins are used
+
+    The number of runs, the time of the last run and the average duration of each plugin are recorded
+    in the storage directory. They are never sent anywhere. The duration is only known for the runs
+    where the plugin is run as a child process, for example when the history is enabled.
+    Use "konveyor config set stats.enabled false" to stop recording.
+    Use "konveyor plugin tidy --unused-since 90d" to find the installed plugins that are no longer used.
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			statsFile, err := stats.GetStats()
+			if err != nil {
+				logrus.Fatalf("failed to get the usage statistics. Error: %q", err)
+			}
+			pluginStats := statsFile.Spec.Plugins
+			sort.SliceStable(pluginStats, func(i, j int) bool { return pluginStats[i].Runs > pluginStats[j].Runs })
+			if output := getOutputFormat(cmd); output != "" {
+				outputStr, err := formatOutput(output, pluginStats)
+				if err != nil {
+					logrus.Fatalf("failed to format the usage statistics. Error: %q", err)
+				}
+				fmt.Print(outputStr)
+				return
+			}
+			if len(pluginStats) == 0 {
+				logrus.Infof("No runs of the plugins were recorded.")
+				return
+			}
+			fmt.Print(formatStats(pluginStats))
+		},
+	}
+	return statsCmd
+}
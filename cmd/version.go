@@ -20,20 +20,67 @@ import (
 	"fmt"
 
 	"github.com/konveyor/cli/lib"
+	"github.com/konveyor/cli/lib/plugin"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/konveyor/cli/lib/update"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+// versionReport contains the version details of konveyor and the plugins.
+type versionReport struct {
+	lib.VersionInfo `yaml:",inline"`
+	Plugins         []types.PluginVersionInfo `yaml:"plugins" json:"plugins"`
+}
+
 // GetVersionCommand returns the version command
 func GetVersionCommand() *cobra.Command {
 	long := false
+	check := false
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version information",
-		Long:  "Print the version information",
-		Run:   func(*cobra.Command, []string) { fmt.Println(lib.GetVersionYaml(long)) },
+		Long: `Print the version information
+
+    The version details include the name, version, platform and source of every plugin.
+    Plugins that declare versionArgs in their plugin YAML are also run to get their own version.
+    Use --output json or --output yaml to get the details in a machine readable format.
+`,
+		Run: func(cmd *cobra.Command, _ []string) {
+			output := getOutputFormat(cmd)
+			if !long && output == "" {
+				fmt.Println(lib.GetVersion())
+			} else {
+				if output == "" {
+					output = types.OUTPUT_FORMAT_YAML
+				}
+				plugins, err := plugin.GetPluginVersionInfos()
+				if err != nil {
+					logrus.Errorf("failed to get the versions of the plugins. Error: %q", err)
+				}
+				outputStr, err := formatOutput(output, versionReport{VersionInfo: lib.GetVersionInfo(), Plugins: plugins})
+				if err != nil {
+					logrus.Fatalf("failed to format the version details. Error: %q", err)
+				}
+				fmt.Print(outputStr)
+			}
+			if !check {
+				return
+			}
+			latest, err := update.GetLatestVersion()
+			if err != nil {
+				logrus.Fatalf("failed to get the latest version of konveyor. Error: %q", err)
+			}
+			if update.IsNewer(latest) {
+				logrus.Infof("A newer version %s is available. Run \"konveyor self-update\" to update.", latest)
+				return
+			}
+			logrus.Infof("konveyor is at the latest version.")
+		},
 	}
 
 	versionCmd.Flags().BoolVarP(&long, "long", "l", false, "Print the version details.")
+	versionCmd.Flags().BoolVar(&check, "check", false, "Check if a newer version is available.")
 
 	return versionCmd
 
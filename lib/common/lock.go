@@ -0,0 +1,82 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+const (
+	// LOCK_FILE_SUFFIX is added to the path of a file to get the path of its lock file.
+	LOCK_FILE_SUFFIX = ".lock"
+)
+
+// LockFile takes an exclusive lock on the file, waiting until other konveyor processes release it.
+// The lock is taken on a separate lock file so that the file itself can be replaced while it is locked.
+// It returns a function that releases the lock.
+func LockFile(path string) (func(), error) {
+	lockPath := path + LOCK_FILE_SUFFIX
+	if err := os.MkdirAll(filepath.Dir(lockPath), types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return nil, fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(lockPath), err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, types.DEFAULT_FILE_PERMISSIONS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock file at path %s . Error: %w", lockPath, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock the file at path %s . Error: %w", lockPath, err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// WriteFileAtomic writes the data to a temporary file and renames it over the file,
+// so that readers and crashes never see a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file in the directory %s . Error: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write to the temporary file at path %s . Error: %w", tmpPath, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync the temporary file at path %s . Error: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close the temporary file at path %s . Error: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("failed to change the permissions of the temporary file at path %s . Error: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace the file at path %s . Error: %w", path, err)
+	}
+	return nil
+}
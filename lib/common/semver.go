@@ -0,0 +1,111 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// comparison is a single operator and version. Example: >= v0.2.0
+type comparison struct {
+	operator string
+	version  string
+}
+
+// NormalizeVersion adds the "v" prefix required by semver if it is missing.
+func NormalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if version != "" && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
+
+// parseConstraint parses a comma separated list of comparisons. Example: >= v0.2.0, < v1.0.0
+func parseConstraint(constraint string) ([]comparison, error) {
+	comparisons := []comparison{}
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		operator := ""
+		for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(part, op) {
+				operator = op
+				break
+			}
+		}
+		version := NormalizeVersion(strings.TrimPrefix(part, operator))
+		if operator == "" {
+			operator = "="
+		}
+		if !semver.IsValid(version) {
+			return nil, fmt.Errorf("the version '%s' in the constraint '%s' is not a valid semantic version", version, constraint)
+		}
+		comparisons = append(comparisons, comparison{operator: operator, version: version})
+	}
+	if len(comparisons) == 0 {
+		return nil, fmt.Errorf("the version constraint '%s' is empty", constraint)
+	}
+	return comparisons, nil
+}
+
+// ValidateConstraint checks that the version constraint can be parsed.
+func ValidateConstraint(constraint string) error {
+	_, err := parseConstraint(constraint)
+	return err
+}
+
+// SatisfiesConstraint returns true if the version satisfies the constraint.
+// The constraint is a comma separated list of comparisons that must all be true. Example: >= v0.2.0, < v1.0.0
+// The supported operators are =, !=, >, >=, <, <= and a version without an operator must match exactly.
+func SatisfiesConstraint(version, constraint string) (bool, error) {
+	comparisons, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	version = NormalizeVersion(version)
+	if !semver.IsValid(version) {
+		return false, fmt.Errorf("the version '%s' is not a valid semantic version", version)
+	}
+	for _, c := range comparisons {
+		result := semver.Compare(version, c.version)
+		ok := false
+		switch c.operator {
+		case "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
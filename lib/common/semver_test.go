@@ -0,0 +1,138 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConstraint(t *testing.T) {
+	testCases := []struct {
+		name       string
+		constraint string
+		want       []comparison
+		wantErr    bool
+	}{
+		{
+			name:       "no operator",
+			constraint: "v0.2.0",
+			want:       []comparison{{operator: "=", version: "v0.2.0"}},
+		},
+		{
+			name:       "missing v prefix",
+			constraint: ">=0.2.0",
+			want:       []comparison{{operator: ">=", version: "v0.2.0"}},
+		},
+		{
+			name:       "range",
+			constraint: ">= v0.2.0, < v1.0.0",
+			want:       []comparison{{operator: ">=", version: "v0.2.0"}, {operator: "<", version: "v1.0.0"}},
+		},
+		{
+			name:       "all operators",
+			constraint: "=v1.0.0,!=v1.0.1,>v0.1.0,>=v0.2.0,<v2.0.0,<=v1.9.0",
+			want: []comparison{
+				{operator: "=", version: "v1.0.0"},
+				{operator: "!=", version: "v1.0.1"},
+				{operator: ">", version: "v0.1.0"},
+				{operator: ">=", version: "v0.2.0"},
+				{operator: "<", version: "v2.0.0"},
+				{operator: "<=", version: "v1.9.0"},
+			},
+		},
+		{
+			name:       "trailing comma",
+			constraint: ">= v0.2.0,",
+			want:       []comparison{{operator: ">=", version: "v0.2.0"}},
+		},
+		{
+			name:       "empty",
+			constraint: " , ",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid version",
+			constraint: ">= latest",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown operator",
+			constraint: "~> v0.2.0",
+			wantErr:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseConstraint(tc.constraint)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    string
+		constraint string
+		want       bool
+		wantErr    bool
+	}{
+		{name: "exact match", version: "v0.2.0", constraint: "v0.2.0", want: true},
+		{name: "exact mismatch", version: "v0.2.1", constraint: "v0.2.0", want: false},
+		{name: "missing v prefix", version: "0.3.0", constraint: ">= 0.2.0", want: true},
+		{name: "not equal", version: "v0.2.0", constraint: "!= v0.2.0", want: false},
+		{name: "greater", version: "v0.2.1", constraint: "> v0.2.0", want: true},
+		{name: "greater or equal", version: "v0.2.0", constraint: ">= v0.2.0", want: true},
+		{name: "less", version: "v0.2.0", constraint: "< v0.2.0", want: false},
+		{name: "less or equal", version: "v0.2.0", constraint: "<= v0.2.0", want: true},
+		{name: "inside the range", version: "v0.9.9", constraint: ">= v0.2.0, < v1.0.0", want: true},
+		{name: "above the range", version: "v1.0.0", constraint: ">= v0.2.0, < v1.0.0", want: false},
+		{name: "below the range", version: "v0.1.9", constraint: ">= v0.2.0, < v1.0.0", want: false},
+		{name: "prerelease is older", version: "v1.0.0-alpha.1", constraint: ">= v1.0.0", want: false},
+		{name: "invalid version", version: "dev", constraint: ">= v0.2.0", wantErr: true},
+		{name: "invalid constraint", version: "v0.2.0", constraint: ">= latest", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SatisfiesConstraint(tc.version, tc.constraint)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %t", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -17,8 +17,13 @@
 package common
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
@@ -60,8 +65,24 @@ func Contains[T comparable](t T, ts []T) bool {
 	return FindIndex(func(t1 T) bool { return t1 == t }, ts) != -1
 }
 
+// storageDir overrides the default storage directory if not empty.
+var storageDir = ""
+
+// SetStorageDir overrides the directory where we store all the plugins.
+func SetStorageDir(dir string) {
+	storageDir = dir
+}
+
 // GetStorageDir returns the directory where we store all the plugins.
 func GetStorageDir() string {
+	if storageDir != "" {
+		return storageDir
+	}
+	return GetDefaultStorageDir()
+}
+
+// GetDefaultStorageDir returns the default storage directory in the user's home directory.
+func GetDefaultStorageDir() string {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		logrus.Warnf("Failed to get the user's home directory. Error: %q", err)
@@ -78,6 +99,16 @@ func GetPluginDir(name string) string {
 	return filepath.Join(GetStorageDir(), types.PLUGINS_DIR, name)
 }
 
+// GetPluginConfigDir returns the path to the directory where the plugin stores its configuration.
+func GetPluginConfigDir(name string) string {
+	return filepath.Join(GetStorageDir(), types.PLUGIN_CONFIG_DIR, name)
+}
+
+// GetPluginDataDir returns the path to the directory where the plugin stores its data.
+func GetPluginDataDir(name string) string {
+	return filepath.Join(GetStorageDir(), types.PLUGIN_DATA_DIR, name)
+}
+
 // GetPlatformAsSingleString returns the Os and Arch as a single string.
 func GetPlatformAsSingleString(os, arch string) string {
 	return os + "-" + arch
@@ -91,3 +122,84 @@ func Keys[K comparable, V interface{}](m map[K]V) []K {
 	}
 	return ks
 }
+
+// ParseDuration parses a duration like time.ParseDuration but also supports days and weeks. Example: 90d, 2w, 36h
+func ParseDuration(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n := strings.TrimSuffix(s, suffix); n != s {
+			count, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, fmt.Errorf("the duration '%s' is invalid. Example: 90d, 2w, 36h", s)
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("the duration '%s' is invalid. Example: 90d, 2w, 36h", s)
+	}
+	return d, nil
+}
+
+// SplitArgs splits a command line into args like a POSIX shell would, without expanding anything.
+// Single and double quotes group words and a backslash escapes the next character outside single quotes.
+// Example: `transform --name "my app"` becomes [transform, --name, my app]
+func SplitArgs(s string) ([]string, error) {
+	args := []string{}
+	current := strings.Builder{}
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("the command line '%s' ends with an unfinished escape", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("the command line '%s' has an unterminated quote", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// JoinArgs joins the args into a command line that SplitArgs splits back into the same args.
+func JoinArgs(args []string) string {
+	quoted := []string{}
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\") {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,286 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FLAGS_KEY_PREFIX is the prefix of the keys that set the default values for command flags.
+	FLAGS_KEY_PREFIX = "flags."
+)
+
+// setting is a config key that is not a command flag.
+type setting struct {
+	env      string
+	field    func(*types.ConfigSpec) *string
+	validate func(string) error
+}
+
+var settings = map[string]setting{
+	"logLevel": {
+		env:   types.ENV_LOG_LEVEL,
+		field: func(s *types.ConfigSpec) *string { return &s.LogLevel },
+		validate: func(v string) error {
+			_, err := logrus.ParseLevel(v)
+			return err
+		},
+	},
+	"output": {
+		env:   types.ENV_OUTPUT,
+		field: func(s *types.ConfigSpec) *string { return &s.Output },
+		validate: func(v string) error {
+			if !common.Contains(v, types.OUTPUT_FORMATS) {
+				return fmt.Errorf("the output format must be one of: %s", strings.Join(types.OUTPUT_FORMATS, ", "))
+			}
+			return nil
+		},
+	},
+	"storageDir": {
+		env:   types.ENV_HOME,
+		field: func(s *types.ConfigSpec) *string { return &s.StorageDir },
+	},
+	"containerRuntime": {
+		env:   types.ENV_CONTAINER_RUNTIME,
+		field: func(s *types.ConfigSpec) *string { return &s.ContainerRuntime },
+	},
+	"sandbox.env": {
+		env:   types.ENV_PREFIX + "SANDBOX_ENV",
+		field: func(s *types.ConfigSpec) *string { return &s.Sandbox.Env },
+		validate: func(v string) error {
+			if !common.Contains(v, types.SANDBOX_ENV_MODES) {
+				return fmt.Errorf("the value must be one of: %s", strings.Join(types.SANDBOX_ENV_MODES, ", "))
+			}
+			return nil
+		},
+	},
+	"sandbox.enabled": {
+		env:   types.ENV_PREFIX + "SANDBOX_ENABLED",
+		field: func(s *types.ConfigSpec) *string { return &s.Sandbox.Enabled },
+		validate: func(v string) error {
+			_, err := strconv.ParseBool(v)
+			return err
+		},
+	},
+	"history.enabled": {
+		env:   types.ENV_PREFIX + "HISTORY_ENABLED",
+		field: func(s *types.ConfigSpec) *string { return &s.History.Enabled },
+		validate: func(v string) error {
+			_, err := strconv.ParseBool(v)
+			return err
+		},
+	},
+	"stats.enabled": {
+		env:   types.ENV_PREFIX + "STATS_ENABLED",
+		field: func(s *types.ConfigSpec) *string { return &s.Stats.Enabled },
+		validate: func(v string) error {
+			_, err := strconv.ParseBool(v)
+			return err
+		},
+	},
+	"pluginRepo.owner": {
+		env:   types.ENV_PREFIX + "PLUGIN_REPO_OWNER",
+		field: func(s *types.ConfigSpec) *string { return &s.PluginRepo.Owner },
+	},
+	"pluginRepo.name": {
+		env:   types.ENV_PREFIX + "PLUGIN_REPO_NAME",
+		field: func(s *types.ConfigSpec) *string { return &s.PluginRepo.Name },
+	},
+	"pluginRepo.branch": {
+		env:   types.ENV_PREFIX + "PLUGIN_REPO_BRANCH",
+		field: func(s *types.ConfigSpec) *string { return &s.PluginRepo.Branch },
+	},
+}
+
+// current is the config that was loaded at startup.
+var current = newConfig()
+
+func newConfig() types.Config {
+	return types.Config{
+		ApiVersion: types.API_VERSION,
+		Kind:       types.CONFIG_FILE_KIND,
+		Metadata:   types.MetadataInfo{Name: "config"},
+	}
+}
+
+// GetConfigPath returns the path to the config file.
+// The config file is always in the default storage directory unless overridden using an environment variable.
+func GetConfigPath() string {
+	if configPath := os.Getenv(types.ENV_CONFIG); configPath != "" {
+		return configPath
+	}
+	return filepath.Join(common.GetDefaultStorageDir(), types.CONFIG_FILE)
+}
+
+// GetConfig returns the config from the config file.
+// It returns an empty config if the file doesn't exist.
+func GetConfig() (types.Config, error) {
+	config := newConfig()
+	configPath := GetConfigPath()
+	configBytes, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return config, fmt.Errorf("failed to read the config file at path %s . Error: %w", configPath, err)
+	}
+	if err := yaml.Unmarshal(configBytes, &config); err != nil {
+		return config, fmt.Errorf("failed to unmarshal the config from yaml. Error: %w", err)
+	}
+	return config, nil
+}
+
+// SaveConfig saves the updated config to file.
+func SaveConfig(config types.Config) error {
+	configPath := GetConfigPath()
+	configDir := filepath.Dir(configPath)
+	if err := os.MkdirAll(configDir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", configDir, err)
+	}
+	configYaml, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the config to yaml. Error: %w", err)
+	}
+	if err := ioutil.WriteFile(configPath, configYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to write the config to a file at path %s . Error: %w", configPath, err)
+	}
+	return nil
+}
+
+// Load reads the config file and applies the log level and the storage directory.
+// The other settings are read by the commands using Resolve.
+// Settings are resolved in the order: environment variable > config file > default.
+func Load() error {
+	// The environment variables still apply if the config file can't be read.
+	config, err := GetConfig()
+	if err == nil {
+		current = config
+	}
+	if logLevel, ok := Resolve("logLevel"); ok {
+		logl, err := logrus.ParseLevel(logLevel)
+		if err != nil {
+			logrus.Errorf("the log level '%s' is invalid, using 'info' log level instead. Error: %q", logLevel, err)
+			logl = logrus.InfoLevel
+		}
+		logrus.SetLevel(logl)
+	}
+	if storageDir, ok := Resolve("storageDir"); ok {
+		common.SetStorageDir(storageDir)
+	}
+	return err
+}
+
+// Resolve returns the value of the key from the environment or the loaded config file, in that order.
+// Returns false if the key is not set in either.
+func Resolve(key string) (string, bool) {
+	if value, ok := os.LookupEnv(GetEnvName(key)); ok && value != "" {
+		return value, true
+	}
+	value, err := Get(current, key)
+	if err != nil || value == "" {
+		return "", false
+	}
+	return value, true
+}
+
+// GetEnvName returns the name of the environment variable that overrides the key.
+func GetEnvName(key string) string {
+	if s, ok := settings[key]; ok {
+		return s.env
+	}
+	name := strings.TrimPrefix(key, FLAGS_KEY_PREFIX)
+	name = strings.NewReplacer(".", "_", "-", "_").Replace(name)
+	return types.ENV_PREFIX + strings.ToUpper(name)
+}
+
+// GetFlagKey returns the key for the default value of a command flag.
+// The command path should not include the root command.
+func GetFlagKey(cmdPath []string, flagName string) string {
+	return FLAGS_KEY_PREFIX + strings.Join(append(cmdPath, flagName), ".")
+}
+
+// IsFlagKey returns true if the key is for the default value of a command flag.
+func IsFlagKey(key string) bool {
+	return strings.HasPrefix(key, FLAGS_KEY_PREFIX) && len(key) > len(FLAGS_KEY_PREFIX)
+}
+
+// GetSettingKeys returns the keys of all the settings that are not command flags.
+func GetSettingKeys() []string {
+	keys := common.Keys(settings)
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of the key in the given config.
+func Get(config types.Config, key string) (string, error) {
+	if IsFlagKey(key) {
+		return config.Spec.Flags[strings.TrimPrefix(key, FLAGS_KEY_PREFIX)], nil
+	}
+	s, ok := settings[key]
+	if !ok {
+		return "", fmt.Errorf("unknown key '%s'", key)
+	}
+	return *s.field(&config.Spec), nil
+}
+
+// Set sets the key to the given value in the given config.
+func Set(config *types.Config, key, value string) error {
+	if IsFlagKey(key) {
+		if config.Spec.Flags == nil {
+			config.Spec.Flags = map[string]string{}
+		}
+		config.Spec.Flags[strings.TrimPrefix(key, FLAGS_KEY_PREFIX)] = value
+		return nil
+	}
+	s, ok := settings[key]
+	if !ok {
+		return fmt.Errorf("unknown key '%s'", key)
+	}
+	if s.validate != nil {
+		if err := s.validate(value); err != nil {
+			return fmt.Errorf("the value '%s' is invalid for the key '%s'. Error: %w", value, key, err)
+		}
+	}
+	*s.field(&config.Spec) = value
+	return nil
+}
+
+// Unset removes the key from the given config.
+func Unset(config *types.Config, key string) error {
+	if IsFlagKey(key) {
+		delete(config.Spec.Flags, strings.TrimPrefix(key, FLAGS_KEY_PREFIX))
+		return nil
+	}
+	s, ok := settings[key]
+	if !ok {
+		return fmt.Errorf("unknown key '%s'", key)
+	}
+	*s.field(&config.Spec) = ""
+	return nil
+}
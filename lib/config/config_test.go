@@ -0,0 +1,166 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// useConfigFile points the config file to a file with the given contents and restores the loaded config afterwards.
+func useConfigFile(t *testing.T, contents string) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), types.CONFIG_FILE)
+	if err := os.WriteFile(configPath, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write the config file at path %s . Error: %q", configPath, err)
+	}
+	t.Setenv(types.ENV_CONFIG, configPath)
+	oldConfig, oldStorageDir, oldLevel := current, common.GetStorageDir(), logrus.GetLevel()
+	t.Cleanup(func() {
+		current = oldConfig
+		common.SetStorageDir(oldStorageDir)
+		logrus.SetLevel(oldLevel)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name           string
+		config         string
+		env            map[string]string
+		wantErr        bool
+		wantStorageDir string
+		wantLevel      logrus.Level
+	}{
+		{
+			name:           "config file",
+			config:         "spec:\n  logLevel: debug\n  storageDir: /from/config\n",
+			wantStorageDir: "/from/config",
+			wantLevel:      logrus.DebugLevel,
+		},
+		{
+			name:           "env overrides the config file",
+			config:         "spec:\n  logLevel: debug\n  storageDir: /from/config\n",
+			env:            map[string]string{types.ENV_HOME: "/from/env", types.ENV_LOG_LEVEL: "warn"},
+			wantStorageDir: "/from/env",
+			wantLevel:      logrus.WarnLevel,
+		},
+		{
+			name:           "env applies to a malformed config file",
+			config:         "spec: [",
+			env:            map[string]string{types.ENV_HOME: "/from/env", types.ENV_LOG_LEVEL: "error"},
+			wantErr:        true,
+			wantStorageDir: "/from/env",
+			wantLevel:      logrus.ErrorLevel,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useConfigFile(t, tc.config)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			err := Load()
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if got := common.GetStorageDir(); got != tc.wantStorageDir {
+				t.Fatalf("expected the storage directory '%s', got '%s'", tc.wantStorageDir, got)
+			}
+			if got := logrus.GetLevel(); got != tc.wantLevel {
+				t.Fatalf("expected the log level '%s', got '%s'", tc.wantLevel, got)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  string
+		env     map[string]string
+		key     string
+		want    string
+		wantSet bool
+	}{
+		{
+			name: "unset",
+			key:  "output",
+		},
+		{
+			name:    "config file",
+			config:  "spec:\n  output: yaml\n",
+			key:     "output",
+			want:    "yaml",
+			wantSet: true,
+		},
+		{
+			name:    "env overrides the config file",
+			config:  "spec:\n  output: yaml\n",
+			env:     map[string]string{types.ENV_OUTPUT: "json"},
+			key:     "output",
+			want:    "json",
+			wantSet: true,
+		},
+		{
+			name:    "empty env is ignored",
+			config:  "spec:\n  output: yaml\n",
+			env:     map[string]string{types.ENV_OUTPUT: ""},
+			key:     "output",
+			want:    "yaml",
+			wantSet: true,
+		},
+		{
+			name:    "flag default",
+			config:  "spec:\n  flags:\n    plugin.list.output: json\n",
+			key:     FLAGS_KEY_PREFIX + "plugin.list.output",
+			want:    "json",
+			wantSet: true,
+		},
+		{
+			name:    "env overrides the flag default",
+			config:  "spec:\n  flags:\n    plugin.list.output: json\n",
+			env:     map[string]string{types.ENV_PREFIX + "PLUGIN_LIST_OUTPUT": "yaml"},
+			key:     FLAGS_KEY_PREFIX + "plugin.list.output",
+			want:    "yaml",
+			wantSet: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useConfigFile(t, tc.config)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if err := Load(); err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			got, ok := Resolve(tc.key)
+			if ok != tc.wantSet || got != tc.want {
+				t.Fatalf("expected ('%s', %t), got ('%s', %t)", tc.want, tc.wantSet, got, ok)
+			}
+		})
+	}
+}
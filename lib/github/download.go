@@ -23,28 +23,67 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/konveyor/cli/lib/types"
 	"github.com/schollz/progressbar/v3"
 	"github.com/sirupsen/logrus"
 )
 
+// IsURL returns true if the uri has an http or https scheme.
+func IsURL(uri string) bool {
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
+}
+
+// GetLocalPath returns the file path for a uri that is not a URL.
+func GetLocalPath(uri string) string {
+	return strings.TrimPrefix(uri, "file://")
+}
+
+// openURI opens a URL or a local file path for reading. It also returns the size of the content if known.
+func openURI(uri string) (io.ReadCloser, int64, error) {
+	if IsURL(uri) {
+		resp, err := http.Get(uri)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to GET the url %s . Error: %w", uri, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, 0, &types.RequestError{StatusCode: resp.StatusCode, Err: fmt.Errorf("failed to GET the url %s", uri)}
+		}
+		return resp.Body, resp.ContentLength, nil
+	}
+	path := GetLocalPath(uri)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open the file at path %s . Error: %w", path, err)
+	}
+	size := int64(-1)
+	if finfo, err := f.Stat(); err == nil {
+		size = finfo.Size()
+	}
+	return f, size, nil
+}
+
 // Download downloads the given url and saves it at the given path.
+// The url can also be a local file path, optionally with a file:// prefix.
 func Download(url string, outputPath string, checkSum string) error {
 	out, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create the output file at path %s . Error: %w", outputPath, err)
 	}
 	defer out.Close()
-	resp, err := http.Get(url)
+	body, size, err := openURI(url)
 	if err != nil {
-		return fmt.Errorf("failed to GET the url %s . Error: %w", url, err)
+		return err
 	}
-	defer resp.Body.Close()
+	defer body.Close()
 	bar := progressbar.DefaultBytes(
-		resp.ContentLength,
+		size,
 		"downloading",
 	)
 	writers := []io.Writer{out, bar}
@@ -52,7 +91,7 @@ func Download(url string, outputPath string, checkSum string) error {
 	if checkSum != "" {
 		writers = append(writers, hash)
 	}
-	n, err := io.Copy(io.MultiWriter(writers...), resp.Body)
+	n, err := io.Copy(io.MultiWriter(writers...), body)
 	if err != nil {
 		return fmt.Errorf("failed to GET the url %s . Error: %w", url, err)
 	}
@@ -68,7 +107,89 @@ func Download(url string, outputPath string, checkSum string) error {
 	return nil
 }
 
+// GetContents returns the contents of the given url or local file path.
+func GetContents(uri string) ([]byte, error) {
+	body, _, err := openURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the contents of %s . Error: %w", uri, err)
+	}
+	return content, nil
+}
+
+// GetSha256 returns the sha256 checksum of the contents of the given url or local file path.
+func GetSha256(uri string) (string, error) {
+	body, _, err := openURI(uri)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", fmt.Errorf("failed to read the contents of %s . Error: %w", uri, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CopyDir copies the contents of the source directory into the target directory.
+func CopyDir(source, target string) error {
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(target, relPath)
+		finfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if err := os.MkdirAll(targetPath, finfo.Mode()); err != nil {
+				return fmt.Errorf("failed to make the directory %s . Error: %w", targetPath, err)
+			}
+			return nil
+		}
+		if !finfo.Mode().IsRegular() {
+			logrus.Warnf("found a file at path %s that is not a regular file. Skipping.", path)
+			return nil
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open the file at path %s . Error: %w", path, err)
+		}
+		defer in.Close()
+		out, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, finfo.Mode())
+		if err != nil {
+			return fmt.Errorf("failed to create the file at path %s . Error: %w", targetPath, err)
+		}
+		defer out.Close()
+		if _, err := io.Copy(out, in); err != nil {
+			return fmt.Errorf("failed to copy the file at path %s to %s . Error: %w", path, targetPath, err)
+		}
+		return nil
+	})
+}
+
+// getArchiveEntryPath returns the path where the entry of the archive is extracted.
+// Entries with absolute paths or paths outside the directory are rejected.
+func getArchiveEntryPath(dir, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if strings.HasPrefix(name, "/") || filepath.IsAbs(cleaned) || filepath.VolumeName(cleaned) != "" ||
+		cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("the archive contains the entry '%s' which is outside the directory it is extracted to", name)
+	}
+	return filepath.Join(dir, cleaned), nil
+}
+
 // ExtractTarGz expands a gzip compressed tar archive.
+// Entries outside the directory containing the archive are rejected.
 func ExtractTarGz(path string) error {
 	archiveDir := filepath.Dir(path)
 	gzippedArchive, err := os.Open(path)
@@ -91,12 +212,18 @@ func ExtractTarGz(path string) error {
 		}
 		switch header.Typeflag {
 		case tar.TypeDir:
-			dirPath := filepath.Join(archiveDir, header.Name)
+			dirPath, err := getArchiveEntryPath(archiveDir, header.Name)
+			if err != nil {
+				return err
+			}
 			if err := os.Mkdir(dirPath, header.FileInfo().Mode()); err != nil {
 				return fmt.Errorf("failed to make the directory %s . Error: %w", dirPath, err)
 			}
 		case tar.TypeReg:
-			filePath := filepath.Join(archiveDir, header.Name)
+			filePath, err := getArchiveEntryPath(archiveDir, header.Name)
+			if err != nil {
+				return err
+			}
 			outFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, header.FileInfo().Mode())
 			if err != nil {
 				return fmt.Errorf("failed to create the file at path %s . Error: %w", filePath, err)
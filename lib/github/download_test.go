@@ -0,0 +1,91 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package github
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTarGz writes a gzip compressed tar archive containing a file for each of the names.
+func writeTarGz(t *testing.T, path string, names []string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create the archive. Error: %q", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := []byte("content of " + name)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write the header for %s . Error: %q", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			t.Fatalf("failed to write the content of %s . Error: %q", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close the tar writer. Error: %q", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to close the gzip writer. Error: %q", err)
+	}
+}
+
+func TestExtractTarGz(t *testing.T) {
+	testCases := []struct {
+		name    string
+		entries []string
+		wantErr bool
+	}{
+		{name: "regular files", entries: []string{"konveyor-foo", "./README.md"}},
+		{name: "parent directory", entries: []string{"../escaped"}, wantErr: true},
+		{name: "nested parent directory", entries: []string{"bin/../../escaped"}, wantErr: true},
+		{name: "absolute path", entries: []string{"/tmp/escaped"}, wantErr: true},
+		{name: "dots in the name", entries: []string{"..foo"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "plugin")
+			if err := os.Mkdir(dir, 0755); err != nil {
+				t.Fatalf("failed to create the directory. Error: %q", err)
+			}
+			archivePath := filepath.Join(dir, "plugin.tar.gz")
+			writeTarGz(t, archivePath, tc.entries)
+			err := ExtractTarGz(archivePath)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error: %t, got the error: %v", tc.wantErr, err)
+			}
+			if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escaped")); err == nil {
+				t.Fatalf("a file was extracted outside the directory %s", dir)
+			}
+			if tc.wantErr {
+				return
+			}
+			for _, entry := range tc.entries {
+				if _, err := os.Stat(filepath.Join(dir, entry)); err != nil {
+					t.Fatalf("expected the entry %s to be extracted. Error: %q", entry, err)
+				}
+			}
+		})
+	}
+}
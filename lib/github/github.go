@@ -28,25 +28,47 @@ import (
 )
 
 const (
-	// REPO_OWNER is the username of the owner of the plugins Github repo.
+	// REPO_OWNER is the default username of the owner of the plugins Github repo.
 	REPO_OWNER = "konveyor"
-	// REPO_NAME is the name of the plugins Github repo.
+	// REPO_NAME is the default name of the plugins Github repo.
 	REPO_NAME = "cli"
-	// REPO_BRANCH is the branch of the plugins Github repo where the metadata for the plugins are stored.
+	// REPO_BRANCH is the default branch of the plugins Github repo where the metadata for the plugins are stored.
 	REPO_BRANCH = "main"
 	// REPO_PLUGINS_DIR is the directory on the Github repo where the metadata for the plugins are stored.
 	REPO_PLUGINS_DIR = "plugins"
 )
 
+// pluginRepo is the Github repo where the metadata for the plugins are stored.
+var pluginRepo = types.PluginRepoConfig{Owner: REPO_OWNER, Name: REPO_NAME, Branch: REPO_BRANCH}
+
+// SetPluginRepo overrides the Github repo where the metadata for the plugins are stored.
+// Empty fields keep their current values.
+func SetPluginRepo(repo types.PluginRepoConfig) {
+	if repo.Owner != "" {
+		pluginRepo.Owner = repo.Owner
+	}
+	if repo.Name != "" {
+		pluginRepo.Name = repo.Name
+	}
+	if repo.Branch != "" {
+		pluginRepo.Branch = repo.Branch
+	}
+}
+
+// GetPluginRepo returns the Github repo where the metadata for the plugins are stored.
+func GetPluginRepo() types.PluginRepoConfig {
+	return pluginRepo
+}
+
 // GetPluginsListFromGithub returns the list of plugins from the Github repo.
 func GetPluginsListFromGithub() ([]string, error) {
 	client := github.NewClient(nil)
 	_, dirContent, resp, err := client.Repositories.GetContents(
 		context.Background(),
-		REPO_OWNER,
-		REPO_NAME,
+		pluginRepo.Owner,
+		pluginRepo.Name,
 		REPO_PLUGINS_DIR,
-		&github.RepositoryContentGetOptions{Ref: REPO_BRANCH},
+		&github.RepositoryContentGetOptions{Ref: pluginRepo.Branch},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list the contents of the plugins folder on the Github repo. Error: %w", err)
@@ -80,10 +102,10 @@ func GetFileFromGithub(path string) ([]byte, error) {
 	client := github.NewClient(nil)
 	fileContent, _, resp, err := client.Repositories.GetContents(
 		context.Background(),
-		REPO_OWNER,
-		REPO_NAME,
+		pluginRepo.Owner,
+		pluginRepo.Name,
 		path,
-		&github.RepositoryContentGetOptions{Ref: REPO_BRANCH},
+		&github.RepositoryContentGetOptions{Ref: pluginRepo.Branch},
 	)
 	if err != nil {
 		return nil, &types.RequestError{
@@ -109,3 +131,49 @@ func GetFileFromGithub(path string) ([]byte, error) {
 	}
 	return content, nil
 }
+
+// GetReleaseAssets returns the files attached to the release with the given tag.
+func GetReleaseAssets(owner, repo, tag string) ([]types.ReleaseAsset, error) {
+	client := github.NewClient(nil)
+	release, resp, err := client.Repositories.GetReleaseByTag(context.Background(), owner, repo, tag)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return nil, &types.RequestError{
+			StatusCode: statusCode,
+			Err:        fmt.Errorf("failed to get the release '%s' of the Github repo %s/%s . Error: %w", tag, owner, repo, err),
+		}
+	}
+	logrus.Debugf("resp: %#v", resp)
+	assets := []types.ReleaseAsset{}
+	for _, asset := range release.Assets {
+		if asset == nil || asset.Name == nil || asset.BrowserDownloadURL == nil {
+			continue
+		}
+		assets = append(assets, types.ReleaseAsset{Name: *asset.Name, Uri: *asset.BrowserDownloadURL})
+	}
+	return assets, nil
+}
+
+// GetLatestReleaseTag returns the tag of the latest release of the Github repo.
+func GetLatestReleaseTag(owner, repo string) (string, error) {
+	client := github.NewClient(nil)
+	release, resp, err := client.Repositories.GetLatestRelease(context.Background(), owner, repo)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return "", &types.RequestError{
+			StatusCode: statusCode,
+			Err:        fmt.Errorf("failed to get the latest release of the Github repo %s/%s . Error: %w", owner, repo, err),
+		}
+	}
+	logrus.Debugf("resp: %#v", resp)
+	if release.TagName == nil {
+		return "", fmt.Errorf("the latest release of the Github repo %s/%s has no tag", owner, repo)
+	}
+	return *release.TagName, nil
+}
@@ -0,0 +1,221 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HISTORY_FILE contains the runs of the plugins, one JSON object per line.
+	HISTORY_FILE = "history.jsonl"
+	// HISTORY_MAX_SIZE is the size in bytes after which the history file is rotated.
+	HISTORY_MAX_SIZE = 5 * 1024 * 1024
+	// HISTORY_MAX_BACKUPS is the number of rotated history files that are kept.
+	HISTORY_MAX_BACKUPS = 2
+	// REDACTED replaces the secrets in the args.
+	REDACTED = "REDACTED"
+)
+
+var (
+	secretName   = regexp.MustCompile(`(?i)(token|passw(or)?d|pwd|secret|credential|auth|api[-_]?key|private[-_]?key|access[-_]?key)`)
+	urlPassword  = regexp.MustCompile(`://([^/@:\s]+):([^/@\s]+)@`)
+	validEnvLike = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// enabled is true if the runs of the plugins should be recorded.
+var enabled = true
+
+// SetEnabled turns the recording of the runs of the plugins on or off.
+func SetEnabled(e bool) {
+	enabled = e
+}
+
+// IsEnabled returns true if the runs of the plugins are recorded.
+func IsEnabled() bool {
+	return enabled
+}
+
+// GetHistoryPath returns the path to the history file.
+func GetHistoryPath() string {
+	return filepath.Join(common.GetStorageDir(), HISTORY_FILE)
+}
+
+// getBackupPath returns the path to the nth rotated history file.
+func getBackupPath(n int) string {
+	return fmt.Sprintf("%s.%d", GetHistoryPath(), n)
+}
+
+// RedactArgs replaces the values of flags and key=value args whose names look like secrets,
+// as well as the passwords in URLs.
+// Example: --token abc, --password=abc and API_KEY=abc all have their values replaced.
+func RedactArgs(args []string) []string {
+	redacted := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := urlPassword.ReplaceAllString(args[i], "://$1:"+REDACTED+"@")
+		if strings.HasPrefix(arg, "-") && arg != "-" && arg != "--" {
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if secretName.MatchString(name) {
+				if hasValue {
+					arg = arg[:strings.Index(arg, "=")+1] + REDACTED
+				} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					redacted = append(redacted, arg, REDACTED)
+					i++
+					continue
+				}
+			}
+		} else if name, _, hasValue := strings.Cut(arg, "="); hasValue && validEnvLike.MatchString(name) && secretName.MatchString(name) {
+			arg = name + "=" + REDACTED
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
+
+// rotate moves the history file aside once it gets too big, keeping HISTORY_MAX_BACKUPS old files.
+func rotate() error {
+	historyPath := GetHistoryPath()
+	finfo, err := os.Stat(historyPath)
+	if err != nil || finfo.Size() < HISTORY_MAX_SIZE {
+		return nil
+	}
+	for n := HISTORY_MAX_BACKUPS; n > 1; n-- {
+		if err := os.Rename(getBackupPath(n-1), getBackupPath(n)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate the history file %s . Error: %w", getBackupPath(n-1), err)
+		}
+	}
+	if err := os.Rename(historyPath, getBackupPath(1)); err != nil {
+		return fmt.Errorf("failed to rotate the history file %s . Error: %w", historyPath, err)
+	}
+	return nil
+}
+
+// Record appends the entry to the history. The args of the entry should already be redacted.
+func Record(entry types.HistoryEntry) error {
+	storageDir := common.GetStorageDir()
+	if err := os.MkdirAll(storageDir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the storage directory %s . Error: %w", storageDir, err)
+	}
+	// Lock the history so that rotating it doesn't lose the runs recorded by other konveyor processes.
+	unlock, err := common.LockFile(GetHistoryPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := rotate(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the history entry to json. Error: %w", err)
+	}
+	historyPath := GetHistoryPath()
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, types.DEFAULT_FILE_PERMISSIONS)
+	if err != nil {
+		return fmt.Errorf("failed to open the history file at path %s . Error: %w", historyPath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write to the history file at path %s . Error: %w", historyPath, err)
+	}
+	return nil
+}
+
+// readEntries reads the entries from a history file. Lines that can't be parsed are skipped.
+func readEntries(path string) ([]types.HistoryEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open the history file at path %s . Error: %w", path, err)
+	}
+	defer f.Close()
+	entries := []types.HistoryEntry{}
+	reader := bufio.NewReader(f)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			entry := types.HistoryEntry{}
+			if err := json.Unmarshal(line, &entry); err != nil {
+				logrus.Debugf("failed to parse the line %d of the history file %s . Skipping. Error: %q", lineNum, path, err)
+			} else {
+				entries = append(entries, entry)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the history file at path %s . Error: %w", path, err)
+		}
+	}
+	return entries, nil
+}
+
+// matches returns true if the entry is selected by the filter.
+func matches(entry types.HistoryEntry, filter types.HistoryFilter) bool {
+	if filter.Plugin != "" && entry.Plugin != filter.Plugin {
+		return false
+	}
+	if !filter.Since.IsZero() && entry.Timestamp.Before(filter.Since) {
+		return false
+	}
+	if filter.Failed && entry.ExitCode == 0 {
+		return false
+	}
+	if filter.Cwd != "" {
+		rel, err := filepath.Rel(filter.Cwd, entry.Cwd)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetEntries returns the entries of the history selected by the filter, oldest first.
+func GetEntries(filter types.HistoryFilter) ([]types.HistoryEntry, error) {
+	paths := []string{}
+	for n := HISTORY_MAX_BACKUPS; n > 0; n-- {
+		paths = append(paths, getBackupPath(n))
+	}
+	paths = append(paths, GetHistoryPath())
+	selected := []types.HistoryEntry{}
+	for _, path := range paths {
+		entries, err := readEntries(path)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, common.Filter(func(e types.HistoryEntry) bool { return matches(e, filter) }, entries)...)
+	}
+	if filter.Limit > 0 && len(selected) > filter.Limit {
+		selected = selected[len(selected)-filter.Limit:]
+	}
+	return selected, nil
+}
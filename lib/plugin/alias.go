@@ -0,0 +1,262 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+var validAliasName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// GetAliasesPath returns the path to the file containing the aliases defined by the user.
+func GetAliasesPath() string {
+	return filepath.Join(common.GetStorageDir(), types.ALIASES_FILE)
+}
+
+// GetUserAliases returns the aliases defined by the user.
+func GetUserAliases() (types.AliasesFile, error) {
+	aliases := types.AliasesFile{
+		ApiVersion: types.API_VERSION,
+		Kind:       types.ALIASES_FILE_KIND,
+		Metadata:   types.MetadataInfo{Name: "aliases"},
+	}
+	aliasesPath := GetAliasesPath()
+	aliasesYaml, err := ioutil.ReadFile(aliasesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return aliases, nil
+		}
+		return aliases, fmt.Errorf("failed to read the aliases file at path %s . Error: %w", aliasesPath, err)
+	}
+	if err := yaml.Unmarshal(aliasesYaml, &aliases); err != nil {
+		return aliases, fmt.Errorf("failed to unmarshal the aliases from yaml. Error: %w", err)
+	}
+	return aliases, nil
+}
+
+// SaveUserAliases saves the aliases defined by the user to file.
+func SaveUserAliases(aliases types.AliasesFile) error {
+	storageDir := common.GetStorageDir()
+	if err := os.MkdirAll(storageDir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the storage directory %s . Error: %w", storageDir, err)
+	}
+	aliasesYaml, err := yaml.Marshal(aliases)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the aliases to yaml. Error: %w", err)
+	}
+	aliasesPath := GetAliasesPath()
+	if err := ioutil.WriteFile(aliasesPath, aliasesYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to write the aliases to a file at path %s . Error: %w", aliasesPath, err)
+	}
+	return nil
+}
+
+// getPluginAliases returns the aliases declared in the metadata of the installed plugins.
+func getPluginAliases(plugins []types.DiscoveredPlugin) []types.Alias {
+	aliases := []types.Alias{}
+	for _, p := range plugins {
+		if p.Installed == nil || p.Installed.Linked {
+			continue
+		}
+		pluginMeta, err := GetPluginMetadataFromLocalCache(p.Name)
+		if err != nil {
+			logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", p.Name, err)
+			continue
+		}
+		for _, name := range pluginMeta.Spec.Aliases {
+			aliases = append(aliases, types.Alias{Name: name, Command: []string{p.Name}, Source: types.ALIAS_SOURCE_PLUGIN})
+		}
+	}
+	return aliases
+}
+
+// getNameCollision returns what the name collides with among the built-in commands and the plugins.
+// Returns an empty string if there is no collision.
+func getNameCollision(name string, plugins []types.DiscoveredPlugin) string {
+	if common.Contains(name, getKonveyorCommands()) {
+		return "a built-in command"
+	}
+	for _, p := range plugins {
+		if p.Name == name || strings.HasPrefix(p.Name, name+" ") {
+			return fmt.Sprintf("the plugin '%s'", p.Name)
+		}
+	}
+	return ""
+}
+
+// GetAliases returns the aliases defined by the user followed by the ones declared by the installed plugins.
+// The aliases declared by plugins are skipped if they collide with a built-in command, a plugin or an earlier alias.
+func GetAliases() ([]types.Alias, error) {
+	plugins, err := GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+	userAliases, err := GetUserAliases()
+	if err != nil {
+		return nil, err
+	}
+	aliases := []types.Alias{}
+	seen := map[string]string{}
+	for _, alias := range userAliases.Spec.Aliases {
+		aliases = append(aliases, types.Alias{Name: alias.Name, Command: alias.Command, Source: types.ALIAS_SOURCE_USER})
+		seen[alias.Name] = "your alias"
+	}
+	for _, alias := range getPluginAliases(plugins) {
+		collision, ok := seen[alias.Name]
+		if !ok {
+			collision = getNameCollision(alias.Name, plugins)
+		}
+		if collision != "" {
+			logrus.Debugf("The alias '%s' of the plugin '%s' collides with %s. Skipping.", alias.Name, alias.Command[0], collision)
+			continue
+		}
+		aliases = append(aliases, alias)
+		seen[alias.Name] = fmt.Sprintf("the alias of the plugin '%s'", alias.Command[0])
+	}
+	return aliases, nil
+}
+
+// FindAlias returns the alias with the given name.
+func FindAlias(name string) (types.Alias, error) {
+	aliases, err := GetAliases()
+	if err != nil {
+		return types.Alias{}, err
+	}
+	idx := common.FindIndex(func(a types.Alias) bool { return a.Name == name }, aliases)
+	if idx == -1 {
+		return types.Alias{}, types.ErrAliasNotFound
+	}
+	return aliases[idx], nil
+}
+
+// ExpandAlias replaces the alias at the start of the args with its command.
+// It returns false if the first arg is not an alias.
+func ExpandAlias(args []string) ([]string, bool, error) {
+	if len(args) == 0 {
+		return args, false, nil
+	}
+	alias, err := FindAlias(args[0])
+	if err != nil {
+		if errors.Is(err, types.ErrAliasNotFound) {
+			return args, false, nil
+		}
+		return args, false, err
+	}
+	return append(append([]string{}, alias.Command...), args[1:]...), true, nil
+}
+
+// SetUserAlias adds or updates an alias defined by the user.
+// The name must not collide with a built-in command, a plugin or an alias declared by a plugin.
+// The command can't start with another alias and the name can't be the start of the command of another alias.
+func SetUserAlias(name string, command []string) error {
+	if !validAliasName.MatchString(name) {
+		return fmt.Errorf("the alias '%s' must start with a letter or digit and contain only letters, digits, dots, dashes and underscores", name)
+	}
+	if len(command) == 0 {
+		return fmt.Errorf("the command for the alias '%s' is empty", name)
+	}
+	if command[0] == name {
+		return fmt.Errorf("the alias '%s' can't expand to itself", name)
+	}
+	plugins, err := GetPlugins()
+	if err != nil {
+		return err
+	}
+	if collision := getNameCollision(name, plugins); collision != "" {
+		return fmt.Errorf("the alias '%s' collides with %s", name, collision)
+	}
+	for _, alias := range getPluginAliases(plugins) {
+		if alias.Name == name {
+			return fmt.Errorf("the alias '%s' collides with the alias of the plugin '%s'", name, alias.Command[0])
+		}
+	}
+	// Aliases are only expanded once so they can't expand to other aliases.
+	allAliases, err := GetAliases()
+	if err != nil {
+		return err
+	}
+	for _, alias := range allAliases {
+		if alias.Name == command[0] {
+			return fmt.Errorf("the alias '%s' can't expand to the alias '%s'. Use the command of that alias instead: %s", name, command[0], common.JoinArgs(alias.Command))
+		}
+		if alias.Name != name && alias.Command[0] == name {
+			return fmt.Errorf("the alias '%s' is used by the alias '%s'. Aliases can't expand to other aliases", name, alias.Name)
+		}
+	}
+	aliases, err := GetUserAliases()
+	if err != nil {
+		return err
+	}
+	if idx := common.FindIndex(func(a types.UserAlias) bool { return a.Name == name }, aliases.Spec.Aliases); idx != -1 {
+		aliases.Spec.Aliases[idx].Command = command
+	} else {
+		aliases.Spec.Aliases = append(aliases.Spec.Aliases, types.UserAlias{Name: name, Command: command})
+	}
+	return SaveUserAliases(aliases)
+}
+
+// UnsetUserAlias removes an alias defined by the user.
+func UnsetUserAlias(name string) error {
+	aliases, err := GetUserAliases()
+	if err != nil {
+		return err
+	}
+	idx := common.FindIndex(func(a types.UserAlias) bool { return a.Name == name }, aliases.Spec.Aliases)
+	if idx == -1 {
+		return types.ErrAliasNotFound
+	}
+	aliases.Spec.Aliases = append(aliases.Spec.Aliases[:idx], aliases.Spec.Aliases[idx+1:]...)
+	return SaveUserAliases(aliases)
+}
+
+// warnAliasCollisions warns about the aliases of a newly installed plugin that can't be used
+// and about the aliases defined by the user that hide the plugin.
+func warnAliasCollisions(name string, declared []string) {
+	userAliases, err := GetUserAliases()
+	if err != nil {
+		logrus.Debugf("failed to get the aliases. Error: %q", err)
+		return
+	}
+	if common.FindIndex(func(a types.UserAlias) bool { return a.Name == name }, userAliases.Spec.Aliases) != -1 {
+		logrus.Warnf("Your alias '%s' hides the plugin '%s'. Use \"konveyor alias unset %s\" to run the plugin.", name, name, name)
+	}
+	if len(declared) == 0 {
+		return
+	}
+	aliases, err := GetAliases()
+	if err != nil {
+		logrus.Debugf("failed to get the aliases. Error: %q", err)
+		return
+	}
+	for _, aliasName := range declared {
+		idx := common.FindIndex(func(a types.Alias) bool { return a.Name == aliasName }, aliases)
+		if idx == -1 || aliases[idx].Source != types.ALIAS_SOURCE_PLUGIN || aliases[idx].Command[0] != name {
+			logrus.Warnf("The alias '%s' of the plugin '%s' can't be used since the name is already taken.", aliasName, name)
+		}
+	}
+}
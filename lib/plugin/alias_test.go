@@ -0,0 +1,134 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/cli/lib/cache"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+)
+
+// useAliasTestPlugins sets up an installed plugin move2kube with the alias m2k and a plugin tackle test on the PATH.
+func useAliasTestPlugins(t *testing.T) {
+	t.Helper()
+	useStorageDir(t)
+	oldCommands := getKonveyorCommands()
+	SetKonveyorCommands([]string{"alias", "plugin", "help"})
+	t.Cleanup(func() { SetKonveyorCommands(oldCommands) })
+	useOS(t, fakeOS{
+		goos: "linux",
+		env:  map[string]string{"PATH": "/usr/bin"},
+		dirs: map[string][]fakeFile{"/usr/bin": {{name: "konveyor-tackle-test", mode: 0755}}},
+	})
+	pluginDir := common.GetPluginDir("move2kube")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", pluginDir, err)
+	}
+	pluginYaml := "metadata:\n  name: move2kube\nspec:\n  aliases: [m2k, tackle, plugin]\n  versions:\n    - version: v0.3.4\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "move2kube.yaml"), []byte(pluginYaml), 0644); err != nil {
+		t.Fatalf("failed to write the plugin yaml. Error: %q", err)
+	}
+	localCache := types.LocalCache{Spec: types.LocalCacheSpec{Installed: []types.InstalledPlugin{{Name: "move2kube", Version: "v0.3.4", Bin: "move2kube"}}}}
+	if err := cache.SaveLocalCache(localCache); err != nil {
+		t.Fatalf("failed to save the local cache. Error: %q", err)
+	}
+}
+
+func TestSetUserAlias(t *testing.T) {
+	useAliasTestPlugins(t)
+	// The aliases are set in order and each case sees the aliases set by the previous ones.
+	testCases := []struct {
+		name    string
+		alias   string
+		command []string
+		wantErr bool
+	}{
+		{name: "plugin with args", alias: "m2kt", command: []string{"move2kube", "transform", "--qa-skip"}},
+		{name: "nested plugin", alias: "tt", command: []string{"tackle", "test"}},
+		{name: "update", alias: "tt", command: []string{"tackle", "test", "--verbose"}},
+		{name: "invalid name", alias: "-x", command: []string{"move2kube"}, wantErr: true},
+		{name: "empty command", alias: "x", command: []string{}, wantErr: true},
+		{name: "itself", alias: "x", command: []string{"x"}, wantErr: true},
+		{name: "built-in command", alias: "plugin", command: []string{"move2kube"}, wantErr: true},
+		{name: "plugin on the PATH", alias: "tackle", command: []string{"move2kube"}, wantErr: true},
+		{name: "installed plugin", alias: "move2kube", command: []string{"tackle", "test"}, wantErr: true},
+		{name: "alias of a plugin", alias: "m2k", command: []string{"tackle", "test"}, wantErr: true},
+		{name: "expands to a user alias", alias: "ff", command: []string{"tt", "x"}, wantErr: true},
+		{name: "expands to a plugin alias", alias: "ff", command: []string{"m2k"}, wantErr: true},
+		{name: "used by another alias", alias: "later", command: []string{"move2kube"}},
+		{name: "expands to a missing command", alias: "g", command: []string{"h"}},
+		{name: "name used by another alias", alias: "h", command: []string{"move2kube"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SetUserAlias(tc.alias, tc.command)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error: %t, got the error: %v", tc.wantErr, err)
+			}
+		})
+	}
+	aliases, err := GetAliases()
+	if err != nil {
+		t.Fatalf("failed to get the aliases. Error: %q", err)
+	}
+	want := []types.Alias{
+		{Name: "m2kt", Command: []string{"move2kube", "transform", "--qa-skip"}, Source: types.ALIAS_SOURCE_USER},
+		{Name: "tt", Command: []string{"tackle", "test", "--verbose"}, Source: types.ALIAS_SOURCE_USER},
+		{Name: "later", Command: []string{"move2kube"}, Source: types.ALIAS_SOURCE_USER},
+		{Name: "g", Command: []string{"h"}, Source: types.ALIAS_SOURCE_USER},
+		// The aliases tackle and plugin of move2kube collide with a plugin and a built-in command.
+		{Name: "m2k", Command: []string{"move2kube"}, Source: types.ALIAS_SOURCE_PLUGIN},
+	}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Fatalf("expected the aliases:\n%+v\ngot:\n%+v", want, aliases)
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	useAliasTestPlugins(t)
+	if err := SetUserAlias("tt", []string{"tackle", "test", "--verbose"}); err != nil {
+		t.Fatalf("failed to set the alias. Error: %q", err)
+	}
+	testCases := []struct {
+		name   string
+		args   []string
+		want   []string
+		wantOk bool
+	}{
+		{name: "user alias with args", args: []string{"tt", "src", "--fix"}, want: []string{"tackle", "test", "--verbose", "src", "--fix"}, wantOk: true},
+		{name: "plugin alias", args: []string{"m2k", "transform"}, want: []string{"move2kube", "transform"}, wantOk: true},
+		{name: "not an alias", args: []string{"move2kube", "tt"}, want: []string{"move2kube", "tt"}},
+		{name: "skipped plugin alias", args: []string{"plugin", "list"}, want: []string{"plugin", "list"}},
+		{name: "no args", args: []string{}, want: []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := ExpandAlias(tc.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if ok != tc.wantOk || !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v (%t), got %v (%t)", tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
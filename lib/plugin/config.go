@@ -0,0 +1,236 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// getPluginConfigPath returns the path to the file containing the values set using "konveyor plugin config".
+func getPluginConfigPath(name string) string {
+	return filepath.Join(common.GetPluginConfigDir(name), types.CONFIG_FILE)
+}
+
+// ensurePluginDirs creates the config and data directories of the plugin.
+func ensurePluginDirs(name string) error {
+	for _, dir := range []string{common.GetPluginConfigDir(name), common.GetPluginDataDir(name)} {
+		if err := os.MkdirAll(dir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+			return fmt.Errorf("failed to create the directory %s . Error: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// PurgePluginDirs removes the config and data directories of the plugin.
+// It returns false if neither of them existed. Invalid names are rejected so that nothing outside the directories is removed.
+func PurgePluginDirs(name string) (bool, error) {
+	if err := ValidatePluginName(name); err != nil {
+		return false, err
+	}
+	found := false
+	for _, root := range []string{types.PLUGIN_CONFIG_DIR, types.PLUGIN_DATA_DIR} {
+		root = filepath.Join(common.GetStorageDir(), root)
+		dir := filepath.Join(root, name)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		found = true
+		if err := removeAllInside(root, dir); err != nil {
+			return found, fmt.Errorf("failed to remove the directory %s . Error: %w", dir, err)
+		}
+	}
+	return found, nil
+}
+
+// GetPluginConfigOptions returns the config keys declared in the metadata of the installed plugin.
+func GetPluginConfigOptions(name string) ([]types.PluginConfigOption, error) {
+	if _, err := GetPluginFromLocalCache(name); err != nil {
+		return nil, err
+	}
+	pluginMeta, err := GetPluginMetadataFromLocalCache(name)
+	if err != nil {
+		logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", name, err)
+		return nil, nil
+	}
+	return pluginMeta.Spec.Config, nil
+}
+
+// parseConfigValue converts the value to the type of the config key, checking that it is one of the allowed values.
+func parseConfigValue(option types.PluginConfigOption, value string) (interface{}, error) {
+	if len(option.Values) > 0 && !common.Contains(value, option.Values) {
+		return nil, fmt.Errorf("the value '%s' is not one of the allowed values: %v", value, option.Values)
+	}
+	switch option.Type {
+	case "", types.CONFIG_TYPE_STRING:
+		return value, nil
+	case types.CONFIG_TYPE_BOOL:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("the value '%s' is not a bool. Error: %w", value, err)
+		}
+		return b, nil
+	case types.CONFIG_TYPE_INT:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("the value '%s' is not an int. Error: %w", value, err)
+		}
+		return i, nil
+	}
+	return nil, fmt.Errorf("the type '%s' of the key '%s' is not supported. Valid types are: %v", option.Type, option.Name, types.CONFIG_TYPES)
+}
+
+// getPluginConfigValues returns the values set by the user for the plugin.
+func getPluginConfigValues(name string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	configPath := getPluginConfigPath(name)
+	configYaml, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return values, fmt.Errorf("failed to read the config of the plugin '%s' at path %s . Error: %w", name, configPath, err)
+	}
+	if err := yaml.Unmarshal(configYaml, &values); err != nil {
+		return values, fmt.Errorf("failed to unmarshal the config of the plugin '%s' from yaml. Error: %w", name, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+// savePluginConfigValues saves the values set by the user for the plugin.
+func savePluginConfigValues(name string, values map[string]interface{}) error {
+	if err := ensurePluginDirs(name); err != nil {
+		return err
+	}
+	configYaml, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the config of the plugin '%s' to yaml. Error: %w", name, err)
+	}
+	configPath := getPluginConfigPath(name)
+	if err := ioutil.WriteFile(configPath, configYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to write the config of the plugin '%s' to a file at path %s . Error: %w", name, configPath, err)
+	}
+	return nil
+}
+
+// findConfigOption returns the config key declared by the plugin with the given name.
+func findConfigOption(options []types.PluginConfigOption, key string) (types.PluginConfigOption, error) {
+	idx := common.FindIndex(func(o types.PluginConfigOption) bool { return o.Name == key }, options)
+	if idx == -1 {
+		return types.PluginConfigOption{}, fmt.Errorf("%w : '%s'", types.ErrConfigKeyNotFound, key)
+	}
+	return options[idx], nil
+}
+
+// GetPluginConfig returns the config keys declared by the plugin along with the values set by the user.
+// Values set for keys the plugin no longer declares are also returned.
+func GetPluginConfig(name string) ([]types.PluginConfigValue, error) {
+	options, err := GetPluginConfigOptions(name)
+	if err != nil {
+		return nil, err
+	}
+	values, err := getPluginConfigValues(name)
+	if err != nil {
+		return nil, err
+	}
+	config := []types.PluginConfigValue{}
+	for _, option := range options {
+		configValue := types.PluginConfigValue{PluginConfigOption: option}
+		if value, ok := values[option.Name]; ok {
+			configValue.Value = fmt.Sprint(value)
+			configValue.IsSet = true
+		}
+		config = append(config, configValue)
+	}
+	undeclared := []string{}
+	for key := range values {
+		if _, err := findConfigOption(options, key); err != nil {
+			undeclared = append(undeclared, key)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, key := range undeclared {
+		config = append(config, types.PluginConfigValue{PluginConfigOption: types.PluginConfigOption{Name: key}, Value: fmt.Sprint(values[key]), IsSet: true})
+	}
+	return config, nil
+}
+
+// GetPluginConfigValue returns the value of the key, or its default if the user has not set it.
+func GetPluginConfigValue(name, key string) (string, error) {
+	config, err := GetPluginConfig(name)
+	if err != nil {
+		return "", err
+	}
+	idx := common.FindIndex(func(c types.PluginConfigValue) bool { return c.Name == key }, config)
+	if idx == -1 {
+		return "", fmt.Errorf("%w : '%s'", types.ErrConfigKeyNotFound, key)
+	}
+	if !config[idx].IsSet {
+		return config[idx].Default, nil
+	}
+	return config[idx].Value, nil
+}
+
+// SetPluginConfigValue sets the value of a key declared by the plugin.
+func SetPluginConfigValue(name, key, value string) error {
+	options, err := GetPluginConfigOptions(name)
+	if err != nil {
+		return err
+	}
+	option, err := findConfigOption(options, key)
+	if err != nil {
+		return err
+	}
+	parsed, err := parseConfigValue(option, value)
+	if err != nil {
+		return err
+	}
+	values, err := getPluginConfigValues(name)
+	if err != nil {
+		return err
+	}
+	values[key] = parsed
+	return savePluginConfigValues(name, values)
+}
+
+// UnsetPluginConfigValue removes the value set by the user so that the default is used again.
+func UnsetPluginConfigValue(name, key string) error {
+	if _, err := GetPluginFromLocalCache(name); err != nil {
+		return err
+	}
+	values, err := getPluginConfigValues(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := values[key]; !ok {
+		return fmt.Errorf("the key '%s' is not set", key)
+	}
+	delete(values, key)
+	return savePluginConfigValues(name, values)
+}
@@ -0,0 +1,223 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/cli/lib/cache"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+)
+
+// useConfigTestPlugin installs a plugin that declares a string, a bool, an int and an enum config key.
+func useConfigTestPlugin(t *testing.T) {
+	t.Helper()
+	useStorageDir(t)
+	pluginDir := common.GetPluginDir("move2kube")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", pluginDir, err)
+	}
+	pluginYaml := `metadata:
+  name: move2kube
+spec:
+  config:
+    - name: name
+      default: foo
+    - name: qa.skip
+      type: bool
+      default: "false"
+    - name: port
+      type: int
+    - name: mode
+      values: [fast, slow]
+  versions:
+    - version: v0.3.4
+`
+	if err := os.WriteFile(filepath.Join(pluginDir, "move2kube.yaml"), []byte(pluginYaml), 0644); err != nil {
+		t.Fatalf("failed to write the plugin yaml. Error: %q", err)
+	}
+	localCache := types.LocalCache{Spec: types.LocalCacheSpec{Installed: []types.InstalledPlugin{{Name: "move2kube", Version: "v0.3.4", Bin: "move2kube"}}}}
+	if err := cache.SaveLocalCache(localCache); err != nil {
+		t.Fatalf("failed to save the local cache. Error: %q", err)
+	}
+}
+
+func TestParseConfigValue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		option  types.PluginConfigOption
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "default type", option: types.PluginConfigOption{}, value: "foo", want: "foo"},
+		{name: "string", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_STRING}, value: "42", want: "42"},
+		{name: "bool", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_BOOL}, value: "true", want: true},
+		{name: "invalid bool", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_BOOL}, value: "yes", wantErr: true},
+		{name: "int", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_INT}, value: "-8", want: -8},
+		{name: "invalid int", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_INT}, value: "1.5", wantErr: true},
+		{name: "allowed value", option: types.PluginConfigOption{Values: []string{"fast", "slow"}}, value: "slow", want: "slow"},
+		{name: "value not allowed", option: types.PluginConfigOption{Values: []string{"fast", "slow"}}, value: "Fast", wantErr: true},
+		{name: "allowed int", option: types.PluginConfigOption{Type: types.CONFIG_TYPE_INT, Values: []string{"1", "2"}}, value: "2", want: 2},
+		{name: "unsupported type", option: types.PluginConfigOption{Name: "foo", Type: "float"}, value: "1.5", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseConfigValue(tc.option, tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateConfigOption(t *testing.T) {
+	testCases := []struct {
+		name      string
+		option    types.PluginConfigOption
+		wantPaths []string
+	}{
+		{
+			name:   "valid",
+			option: types.PluginConfigOption{Name: "qa.skip", Type: types.CONFIG_TYPE_BOOL, Default: "false", Description: "skip the questions"},
+		},
+		{
+			name:      "missing name and description",
+			option:    types.PluginConfigOption{},
+			wantPaths: []string{"spec.config[0].name", "spec.config[0].description"},
+		},
+		{
+			name:      "invalid name",
+			option:    types.PluginConfigOption{Name: "1st", Description: "foo"},
+			wantPaths: []string{"spec.config[0].name"},
+		},
+		{
+			name:      "unknown type",
+			option:    types.PluginConfigOption{Name: "foo", Type: "float", Default: "1.5", Description: "foo"},
+			wantPaths: []string{"spec.config[0].type"},
+		},
+		{
+			name:      "values of the wrong type",
+			option:    types.PluginConfigOption{Name: "foo", Type: types.CONFIG_TYPE_INT, Values: []string{"1", "two"}, Description: "foo"},
+			wantPaths: []string{"spec.config[0].values[1]"},
+		},
+		{
+			name:      "default of the wrong type",
+			option:    types.PluginConfigOption{Name: "foo", Type: types.CONFIG_TYPE_BOOL, Default: "no", Description: "foo"},
+			wantPaths: []string{"spec.config[0].default"},
+		},
+		{
+			name:      "default not allowed",
+			option:    types.PluginConfigOption{Name: "foo", Values: []string{"fast", "slow"}, Default: "medium", Description: "foo"},
+			wantPaths: []string{"spec.config[0].default"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &validator{}
+			v.validateConfigOption("spec.config[0]", tc.option)
+			gotPaths := []string{}
+			for _, d := range v.diagnostics {
+				gotPaths = append(gotPaths, d.Path)
+			}
+			if len(tc.wantPaths) == 0 {
+				tc.wantPaths = []string{}
+			}
+			if !reflect.DeepEqual(gotPaths, tc.wantPaths) {
+				t.Fatalf("expected diagnostics for %v, got %v", tc.wantPaths, v.diagnostics)
+			}
+		})
+	}
+}
+
+func TestSetPluginConfigValue(t *testing.T) {
+	useConfigTestPlugin(t)
+	// The values are set in order and each case sees the values set by the previous ones.
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "string", key: "name", value: "bar", want: "bar"},
+		{name: "bool", key: "qa.skip", value: "1", want: "true"},
+		{name: "invalid bool", key: "qa.skip", value: "maybe", want: "true", wantErr: true},
+		{name: "int", key: "port", value: "8080", want: "8080"},
+		{name: "invalid int", key: "port", value: "http", want: "8080", wantErr: true},
+		{name: "allowed value", key: "mode", value: "fast", want: "fast"},
+		{name: "value not allowed", key: "mode", value: "medium", want: "fast", wantErr: true},
+		{name: "undeclared key", key: "foo", value: "bar", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SetPluginConfigValue("move2kube", tc.key, tc.value)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %q", err)
+			}
+			if tc.want == "" {
+				return
+			}
+			got, err := GetPluginConfigValue("move2kube", tc.key)
+			if err != nil {
+				t.Fatalf("failed to get the value of the key '%s'. Error: %q", tc.key, err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected the value '%s', got '%s'", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGetPluginConfigValue(t *testing.T) {
+	useConfigTestPlugin(t)
+	if got, err := GetPluginConfigValue("move2kube", "name"); err != nil || got != "foo" {
+		t.Fatalf("expected the default value 'foo', got '%s'. Error: %v", got, err)
+	}
+	if err := SetPluginConfigValue("move2kube", "name", "bar"); err != nil {
+		t.Fatalf("failed to set the value. Error: %q", err)
+	}
+	if err := UnsetPluginConfigValue("move2kube", "name"); err != nil {
+		t.Fatalf("failed to unset the value. Error: %q", err)
+	}
+	if got, err := GetPluginConfigValue("move2kube", "name"); err != nil || got != "foo" {
+		t.Fatalf("expected the default value 'foo' after unsetting the key, got '%s'. Error: %v", got, err)
+	}
+	if err := UnsetPluginConfigValue("move2kube", "name"); err == nil {
+		t.Fatalf("expected an error when unsetting a key that is not set")
+	}
+	if _, err := GetPluginConfigValue("move2kube", "foo"); !errors.Is(err, types.ErrConfigKeyNotFound) {
+		t.Fatalf("expected the error %q, got %q", types.ErrConfigKeyNotFound, err)
+	}
+}
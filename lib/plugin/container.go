@@ -0,0 +1,179 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// containerRuntime is the name or path of the executable used to run container image plugins. Detected if empty.
+var containerRuntime = ""
+
+// SetContainerRuntime sets the executable used to run container image plugins.
+func SetContainerRuntime(runtime string) {
+	containerRuntime = runtime
+}
+
+// GetContainerRuntime returns the path to the executable used to run container image plugins.
+// It uses the configured runtime if there is one, otherwise the first of docker and podman found on the PATH.
+func GetContainerRuntime() (string, error) {
+	if containerRuntime != "" {
+		runtimePath, err := exec.LookPath(containerRuntime)
+		if err != nil {
+			return "", fmt.Errorf("failed to find the container runtime '%s'. Error: %w", containerRuntime, err)
+		}
+		return runtimePath, nil
+	}
+	for _, runtime := range types.CONTAINER_RUNTIMES {
+		if runtimePath, err := exec.LookPath(runtime); err == nil {
+			return runtimePath, nil
+		}
+	}
+	return "", fmt.Errorf("%w : one of %s must be installed and on the PATH to run container image plugins", types.ErrUnmetRequirements, strings.Join(types.CONTAINER_RUNTIMES, ", "))
+}
+
+// runContainerRuntime runs the container runtime with the given args, showing its output.
+func runContainerRuntime(args ...string) error {
+	runtimePath, err := GetContainerRuntime()
+	if err != nil {
+		return err
+	}
+	runtimeCmd := exec.Command(runtimePath, args...)
+	runtimeCmd.Stdout = os.Stderr
+	runtimeCmd.Stderr = os.Stderr
+	if err := runtimeCmd.Run(); err != nil {
+		return fmt.Errorf("failed to run '%s %s'. Error: %w", runtimePath, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// PullImage pulls the container image so that the first run of the plugin doesn't have to.
+func PullImage(image string) error {
+	logrus.Infof("Pulling the container image %s", image)
+	return runContainerRuntime("pull", image)
+}
+
+// RemoveImage removes the container image from the container runtime.
+func RemoveImage(image string) error {
+	logrus.Infof("Removing the container image %s", image)
+	return runContainerRuntime("rmi", image)
+}
+
+// isTerminal returns true if the file is a terminal.
+func isTerminal(f *os.File) bool {
+	finfo, err := f.Stat()
+	return err == nil && finfo.Mode()&os.ModeCharDevice != 0
+}
+
+// getContainerEnvNames returns the names of the environment variables passed into the container.
+// These are the KONVEYOR_* variables, the variables declared in the plugin metadata and the ones requested in its permissions.
+func getContainerEnvNames(version types.PluginVersionMetadata, environment []string) []string {
+	names := map[string]bool{}
+	for name := range version.Env {
+		names[name] = true
+	}
+	patterns := []string{types.ENV_PREFIX + "*"}
+	if version.Permissions.Network {
+		patterns = append(patterns, types.NETWORK_ENV_ALLOWLIST...)
+	}
+	patterns = append(patterns, version.Permissions.Env...)
+	for _, kv := range filterEnvironment(environment, patterns) {
+		name, _, _ := strings.Cut(kv, "=")
+		names[name] = true
+	}
+	keys := common.Keys(names)
+	sort.Strings(keys)
+	return keys
+}
+
+// getContainerRunArgs returns the args for the container runtime that run the image with the plugin args.
+// The current working directory is mounted at CONTAINER_WORKDIR which is also the working directory inside the container.
+// When the sandbox is enabled the container has no network unless the plugin requests it.
+// A TTY is only allocated for interactive runs where konveyor itself is attached to a terminal.
+func getContainerRunArgs(installed types.InstalledPlugin, args, environment []string, interactive bool) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current working directory. Error: %w", err)
+	}
+	runArgs := []string{"run", "--rm", "-i"}
+	if interactive && isTerminal(os.Stdin) && isTerminal(os.Stdout) {
+		runArgs = append(runArgs, "-t")
+	}
+	version, err := GetPluginVersionMetadata(installed)
+	if err != nil {
+		logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", installed.Name, err)
+	}
+	if sandboxOptions.Enabled && !version.Permissions.Network {
+		runArgs = append(runArgs, "--network", "none")
+	}
+	runArgs = append(runArgs, "-v", cwd+":"+types.CONTAINER_WORKDIR, "-w", types.CONTAINER_WORKDIR)
+	// The config and data directories are mounted at the same location so that the KONVEYOR_PLUGIN_*_DIR variables work inside the container.
+	for _, dir := range []string{common.GetPluginConfigDir(installed.Name), common.GetPluginDataDir(installed.Name)} {
+		if _, err := os.Stat(dir); err == nil {
+			runArgs = append(runArgs, "-v", dir+":"+filepath.ToSlash(dir))
+		}
+	}
+	// The paths requested in the permissions are mounted at the same location inside the container.
+	for _, path := range version.Permissions.Paths {
+		path = expandPath(path)
+		if _, err := os.Stat(path); err != nil {
+			logrus.Debugf("The path %s requested by the plugin '%s' does not exist. Skipping. Error: %q", path, installed.Name, err)
+			continue
+		}
+		runArgs = append(runArgs, "-v", path+":"+filepath.ToSlash(path))
+	}
+	// Only the names are passed so that the values are read from the environment and don't show up in the process list.
+	for _, name := range getContainerEnvNames(version, environment) {
+		runArgs = append(runArgs, "-e", name)
+	}
+	runArgs = append(runArgs, installed.Image)
+	return append(runArgs, args...), nil
+}
+
+// GetPluginCommand returns the executable and the args that run the plugin with the given args.
+// Container image plugins are run using the container runtime and script plugins using their interpreter.
+// Interactive is true when the plugin is attached to the user's terminal instead of having its output captured.
+func GetPluginCommand(p types.DiscoveredPlugin, args, environment []string, interactive bool) (string, []string, error) {
+	if p.Installed != nil && p.Installed.Interpreter != "" {
+		interpreterPath, err := CheckInterpreter(p.Installed.Interpreter, false)
+		if err != nil {
+			return "", nil, err
+		}
+		return interpreterPath, append([]string{p.Path}, args...), nil
+	}
+	if p.Installed == nil || p.Installed.Image == "" {
+		return p.Path, args, nil
+	}
+	runtimePath, err := GetContainerRuntime()
+	if err != nil {
+		return "", nil, err
+	}
+	runArgs, err := getContainerRunArgs(*p.Installed, args, environment, interactive)
+	if err != nil {
+		return "", nil, err
+	}
+	return runtimePath, runArgs, nil
+}
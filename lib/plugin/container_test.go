@@ -0,0 +1,205 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+)
+
+const testPluginYaml = `apiVersion: v1
+kind: Plugin
+metadata:
+  name: scan
+spec:
+  versions:
+    - version: v1.0.0
+      env:
+        SCAN_MODE: fast
+      permissions:
+        paths:
+          - %s
+          - %s
+        env:
+          - AWS_*
+      platforms:
+        - selector:
+            matchLabels:
+              os: linux
+          image: quay.io/konveyor/scan:v1.0.0
+`
+
+// useStorageDir points the storage directory at a temporary directory until the end of the test.
+func useStorageDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	common.SetStorageDir(dir)
+	t.Cleanup(func() { common.SetStorageDir("") })
+	return dir
+}
+
+// useSandboxOptions sets the sandbox options until the end of the test.
+func useSandboxOptions(t *testing.T, options types.SandboxOptions) {
+	t.Helper()
+	previous := sandboxOptions
+	SetSandboxOptions(options)
+	t.Cleanup(func() { SetSandboxOptions(previous) })
+}
+
+// writeStubRuntime puts a docker executable that prints its args one per line on the PATH.
+func writeStubRuntime(t *testing.T) string {
+	t.Helper()
+	binDir := t.TempDir()
+	stubPath := filepath.Join(binDir, "docker")
+	if err := os.WriteFile(stubPath, []byte("#!/bin/sh\nfor arg in \"$@\"; do echo \"$arg\"; done\n"), 0755); err != nil {
+		t.Fatalf("failed to write the stub container runtime. Error: %q", err)
+	}
+	t.Setenv("PATH", binDir)
+	return stubPath
+}
+
+func TestGetPluginCommandForImage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub container runtime is a shell script")
+	}
+	stubPath := writeStubRuntime(t)
+	storageDir := useStorageDir(t)
+	useSandboxOptions(t, types.SandboxOptions{Enabled: true})
+	SetContainerRuntime("")
+
+	kubeDir := filepath.Join(t.TempDir(), ".kube")
+	if err := os.Mkdir(kubeDir, 0755); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", kubeDir, err)
+	}
+	missingDir := filepath.Join(storageDir, "missing")
+	pluginDir := common.GetPluginDir("scan")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", pluginDir, err)
+	}
+	pluginYaml := fmt.Sprintf(testPluginYaml, kubeDir, missingDir)
+	if err := os.WriteFile(filepath.Join(pluginDir, "scan.yaml"), []byte(pluginYaml), 0644); err != nil {
+		t.Fatalf("failed to write the plugin yaml. Error: %q", err)
+	}
+	if err := ensurePluginDirs("scan"); err != nil {
+		t.Fatalf("failed to create the plugin directories. Error: %q", err)
+	}
+
+	p := types.DiscoveredPlugin{
+		Name: "scan",
+		Path: filepath.Join(pluginDir, "scan"),
+		Installed: &types.InstalledPlugin{
+			Name:     "scan",
+			Version:  "v1.0.0",
+			Platform: "linux-amd64",
+			Image:    "quay.io/konveyor/scan:v1.0.0",
+		},
+	}
+	environment := []string{
+		"KONVEYOR_PLUGIN_NAME=scan",
+		"KONVEYOR_HOME=" + storageDir,
+		"AWS_REGION=us-east-1",
+		"SCAN_MODE=fast",
+		"GITHUB_TOKEN=secret",
+		"HTTPS_PROXY=http://proxy:3128",
+	}
+	bin, args, err := GetPluginCommand(p, []string{"--fix", "src"}, environment, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if bin != stubPath {
+		t.Fatalf("expected the container runtime %s, got %s", stubPath, bin)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get the current working directory. Error: %q", err)
+	}
+	configDir, dataDir := common.GetPluginConfigDir("scan"), common.GetPluginDataDir("scan")
+	want := []string{
+		"run", "--rm", "-i",
+		"--network", "none",
+		"-v", cwd + ":" + types.CONTAINER_WORKDIR, "-w", types.CONTAINER_WORKDIR,
+		"-v", configDir + ":" + configDir,
+		"-v", dataDir + ":" + dataDir,
+		"-v", kubeDir + ":" + kubeDir,
+		"-e", "AWS_REGION",
+		"-e", "KONVEYOR_HOME",
+		"-e", "KONVEYOR_PLUGIN_NAME",
+		"-e", "SCAN_MODE",
+		"quay.io/konveyor/scan:v1.0.0",
+		"--fix", "src",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("expected the args:\n%v\ngot:\n%v", want, args)
+	}
+
+	// Run the stub to make sure the args reach the container runtime unchanged.
+	output, err := exec.Command(bin, args...).Output()
+	if err != nil {
+		t.Fatalf("failed to run the stub container runtime. Error: %q", err)
+	}
+	if got := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the container runtime to receive:\n%v\ngot:\n%v", want, got)
+	}
+}
+
+func TestGetPluginCommandForImageWithNetwork(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub container runtime is a shell script")
+	}
+	writeStubRuntime(t)
+	useStorageDir(t)
+	useSandboxOptions(t, types.SandboxOptions{Enabled: true})
+	SetContainerRuntime("")
+
+	pluginDir := common.GetPluginDir("fetch")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatalf("failed to create the directory %s . Error: %q", pluginDir, err)
+	}
+	pluginYaml := "spec:\n  versions:\n    - version: v1.0.0\n      permissions:\n        network: true\n"
+	if err := os.WriteFile(filepath.Join(pluginDir, "fetch.yaml"), []byte(pluginYaml), 0644); err != nil {
+		t.Fatalf("failed to write the plugin yaml. Error: %q", err)
+	}
+	p := types.DiscoveredPlugin{
+		Name:      "fetch",
+		Installed: &types.InstalledPlugin{Name: "fetch", Version: "v1.0.0", Image: "quay.io/konveyor/fetch:v1.0.0"},
+	}
+	_, args, err := GetPluginCommand(p, nil, []string{"HTTPS_PROXY=http://proxy:3128", "GITHUB_TOKEN=secret"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	if common.Contains("--network", args) {
+		t.Fatalf("expected the container to have network access, got the args %v", args)
+	}
+	if !common.Contains("HTTPS_PROXY", args) {
+		t.Fatalf("expected the proxy variable to be passed, got the args %v", args)
+	}
+	if common.Contains("GITHUB_TOKEN", args) {
+		t.Fatalf("expected the token not to be passed, got the args %v", args)
+	}
+	if args[len(args)-1] != "quay.io/konveyor/fetch:v1.0.0" {
+		t.Fatalf("expected the image to be the last arg, got the args %v", args)
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PLUGIN_DESCRIBE_TIMEOUT is the maximum time a plugin is given to describe its commands.
+	PLUGIN_DESCRIBE_TIMEOUT = 5 * time.Second
+)
+
+// getPluginDescriptionPath returns the path where the description of the plugin's commands is cached.
+func getPluginDescriptionPath(name string) string {
+	return filepath.Join(common.GetPluginDir(name), types.PLUGIN_DESCRIPTION_FILE)
+}
+
+// describePlugin runs the plugin with the introspection arg and parses the description it prints.
+func describePlugin(p types.DiscoveredPlugin) (types.PluginDescription, error) {
+	description := types.PluginDescription{}
+	ctx, cancel := context.WithTimeout(context.Background(), PLUGIN_DESCRIBE_TIMEOUT)
+	defer cancel()
+	environment := GetPluginEnvironment(p, "")
+	executable, args, err := GetPluginCommand(p, []string{types.PLUGIN_DESCRIBE_ARG}, environment, false)
+	if err != nil {
+		return description, err
+	}
+	describeCmd := CommandContext(ctx, executable, args...)
+	describeCmd.Env = environment
+	output, err := describeCmd.Output()
+	if ctx.Err() != nil {
+		return description, fmt.Errorf("the plugin did not describe its commands within %s", PLUGIN_DESCRIBE_TIMEOUT)
+	}
+	if err != nil {
+		return description, fmt.Errorf("%w : failed to run the plugin with the arg %s . Error: %v", types.ErrNoPluginDescription, types.PLUGIN_DESCRIBE_ARG, err)
+	}
+	if err := json.Unmarshal(output, &description); err != nil {
+		return description, fmt.Errorf("%w : failed to parse the output of the plugin as JSON. Error: %v", types.ErrNoPluginDescription, err)
+	}
+	if description.ApiVersion != types.API_VERSION || description.Kind != types.PLUGIN_DESCRIPTION_KIND {
+		return description, fmt.Errorf("%w : the output of the plugin must have the apiVersion '%s' and the kind '%s'", types.ErrNoPluginDescription, types.API_VERSION, types.PLUGIN_DESCRIPTION_KIND)
+	}
+	return description, nil
+}
+
+// writePluginDescriptionFile writes the cached description of the plugin.
+func writePluginDescriptionFile(descriptionPath string, descriptionJson []byte) error {
+	if err := os.MkdirAll(filepath.Dir(descriptionPath), types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the directory for the plugin description. Error: %w", err)
+	}
+	if err := ioutil.WriteFile(descriptionPath, descriptionJson, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to write the plugin description to the path %s . Error: %w", descriptionPath, err)
+	}
+	return nil
+}
+
+// CachePluginDescription asks an installed or linked plugin to describe its commands and caches the description.
+// If the plugin doesn't describe its commands an empty description is cached instead, so that the plugin
+// is not run again every time the help or the completions are shown.
+func CachePluginDescription(p types.DiscoveredPlugin) error {
+	descriptionPath := getPluginDescriptionPath(p.Name)
+	description, err := describePlugin(p)
+	if err != nil {
+		if err := writePluginDescriptionFile(descriptionPath, nil); err != nil {
+			logrus.Debugf("failed to cache the missing description of the plugin '%s'. Error: %q", p.Name, err)
+		}
+		return err
+	}
+	descriptionJson, err := json.MarshalIndent(description, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the plugin description to json. Error: %w", err)
+	}
+	return writePluginDescriptionFile(descriptionPath, descriptionJson)
+}
+
+// cachePluginDescriptionOnInstall caches the description of a plugin that was just installed or linked.
+// Plugins are not required to describe their commands so failures are only logged.
+func cachePluginDescriptionOnInstall(installed types.InstalledPlugin) {
+	p := types.DiscoveredPlugin{Name: installed.Name, Path: GetPluginBinPath(installed), Installed: &installed}
+	if err := CachePluginDescription(p); err != nil {
+		if errors.Is(err, types.ErrNoPluginDescription) {
+			logrus.Debugf("The plugin '%s' does not describe its commands. Error: %q", installed.Name, err)
+			return
+		}
+		logrus.Warnf("failed to get the description of the commands of the plugin '%s'. Error: %q", installed.Name, err)
+	}
+}
+
+// GetPluginDescription returns the cached description of the commands of an installed or linked plugin.
+// The description of a linked plugin is refreshed when its executable is newer than the description.
+// An empty description file means the plugin doesn't describe its commands.
+func GetPluginDescription(p types.DiscoveredPlugin) (types.PluginDescription, error) {
+	description := types.PluginDescription{}
+	if p.Installed == nil {
+		return description, types.ErrNoPluginDescription
+	}
+	descriptionPath := getPluginDescriptionPath(p.Name)
+	if p.Installed.Linked {
+		binInfo, binErr := os.Stat(p.Path)
+		descriptionInfo, err := os.Stat(descriptionPath)
+		if binErr == nil && (err != nil || binInfo.ModTime().After(descriptionInfo.ModTime())) {
+			logrus.Debugf("Refreshing the description of the linked plugin '%s'", p.Name)
+			if err := CachePluginDescription(p); err != nil {
+				return description, err
+			}
+		}
+	}
+	descriptionJson, err := ioutil.ReadFile(descriptionPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return description, types.ErrNoPluginDescription
+		}
+		return description, fmt.Errorf("failed to read the plugin description at path %s . Error: %w", descriptionPath, err)
+	}
+	if len(descriptionJson) == 0 {
+		return description, types.ErrNoPluginDescription
+	}
+	if err := json.Unmarshal(descriptionJson, &description); err != nil {
+		return description, fmt.Errorf("failed to parse the plugin description at path %s . Error: %w", descriptionPath, err)
+	}
+	return description, nil
+}
+
+// formatFlagName returns the flag as it is shown in the help. Example: -s, --source string
+func formatFlagName(flag types.FlagDescription) string {
+	name := "--" + flag.Name
+	if flag.Shorthand != "" {
+		name = "-" + flag.Shorthand + ", " + name
+	}
+	if flag.Type != "bool" {
+		typ := flag.Type
+		if typ == "" {
+			typ = "string"
+		}
+		name += " " + typ
+	}
+	return name
+}
+
+// FormatPluginCommands formats the commands and flags described by the plugin as an indented tree.
+func FormatPluginCommands(name string, spec types.CommandDescription) string {
+	w := &strings.Builder{}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	var addCommand func(path string, command types.CommandDescription, indent string)
+	addCommand = func(path string, command types.CommandDescription, indent string) {
+		fmt.Fprintf(tw, "%s%s\t%s\n", indent, path, command.Short)
+		for _, flag := range command.Flags {
+			fmt.Fprintf(tw, "%s    %s\t%s\n", indent, formatFlagName(flag), flag.Usage)
+		}
+		for _, subCommand := range command.Commands {
+			addCommand(path+" "+subCommand.Name, subCommand, indent+"  ")
+		}
+	}
+	addCommand(name, spec, "")
+	tw.Flush()
+	return w.String()
+}
@@ -0,0 +1,77 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+func TestGetPluginDescriptionCachesMissingDescription(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the plugin is a shell script")
+	}
+	useStorageDir(t)
+	dir := t.TempDir()
+	runsPath := filepath.Join(dir, "runs")
+	binPath := filepath.Join(dir, "konveyor-foo")
+	// The plugin records every run and fails since it doesn't support the introspection arg.
+	script := "#!/bin/sh\necho run >> " + runsPath + "\nexit 1\n"
+	if err := os.WriteFile(binPath, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write the plugin. Error: %q", err)
+	}
+	p := types.DiscoveredPlugin{
+		Name:      "foo",
+		Path:      binPath,
+		Installed: &types.InstalledPlugin{Name: "foo", Version: "dev", Bin: "konveyor-foo", Linked: true, LinkPath: dir},
+	}
+	getRuns := func() int {
+		runs, err := os.ReadFile(runsPath)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatalf("failed to read the runs of the plugin. Error: %q", err)
+		}
+		return strings.Count(string(runs), "run")
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := GetPluginDescription(p); !errors.Is(err, types.ErrNoPluginDescription) {
+			t.Fatalf("expected the error %q, got %v", types.ErrNoPluginDescription, err)
+		}
+	}
+	if runs := getRuns(); runs != 1 {
+		t.Fatalf("expected the plugin to be run once, it was run %d times", runs)
+	}
+
+	// Rebuilding the plugin makes it describe itself again.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(binPath, later, later); err != nil {
+		t.Fatalf("failed to change the modification time of the plugin. Error: %q", err)
+	}
+	if _, err := GetPluginDescription(p); !errors.Is(err, types.ErrNoPluginDescription) {
+		t.Fatalf("expected the error %q, got %v", types.ErrNoPluginDescription, err)
+	}
+	if runs := getRuns(); runs != 2 {
+		t.Fatalf("expected the plugin to be run again after it changed, it was run %d times", runs)
+	}
+}
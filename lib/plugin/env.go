@@ -0,0 +1,109 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/cli/lib"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// setEnv sets the variable in the list of environment variables.
+// If the variable is already set it is only changed when override is true.
+func setEnv(environment []string, key, value string, override bool) []string {
+	prefix := key + "="
+	idx := common.FindIndex(func(kv string) bool { return strings.HasPrefix(kv, prefix) }, environment)
+	if idx == -1 {
+		return append(environment, prefix+value)
+	}
+	if override {
+		environment[idx] = prefix + value
+	}
+	return environment
+}
+
+// GetPluginDirectory returns the directory containing the plugin's files.
+func GetPluginDirectory(p types.DiscoveredPlugin) string {
+	if p.Installed != nil {
+		return GetInstalledPluginDir(*p.Installed)
+	}
+	return filepath.Dir(p.Path)
+}
+
+// GetPluginVersionMetadata returns the metadata for the installed version of the plugin.
+func GetPluginVersionMetadata(installed types.InstalledPlugin) (types.PluginVersionMetadata, error) {
+	pluginMeta, err := GetPluginMetadataFromLocalCache(installed.Name)
+	if err != nil {
+		return types.PluginVersionMetadata{}, err
+	}
+	idx := common.FindIndex(func(v types.PluginVersionMetadata) bool { return v.Version == installed.Version }, pluginMeta.Spec.Versions)
+	if idx == -1 {
+		return types.PluginVersionMetadata{}, types.ErrPluginNotInstalled
+	}
+	return pluginMeta.Spec.Versions[idx], nil
+}
+
+// GetPluginEnvironment returns the environment variables that the plugin should be run with.
+// It adds the KONVEYOR_* variables describing the konveyor installation and the plugin to the current environment
+// along with the defaults declared in the plugin metadata. The config and data directories of installed plugins are created if needed.
+// Installed plugins only receive the allowed variables and the ones they request in their permissions unless the sandbox.env setting is all.
+func GetPluginEnvironment(p types.DiscoveredPlugin, output string) []string {
+	environment := os.Environ()
+	if p.Installed != nil {
+		version, err := GetPluginVersionMetadata(*p.Installed)
+		if err != nil {
+			logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", p.Name, err)
+		}
+		if isManagedPlugin(p) && !sandboxOptions.PassAllEnv {
+			environment = filterEnvironment(environment, getAllowedEnv(version))
+		}
+		keys := common.Keys(version.Env)
+		sort.Strings(keys)
+		for _, key := range keys {
+			environment = setEnv(environment, key, version.Env[key], false)
+		}
+	}
+	konveyorBin, err := os.Executable()
+	if err != nil {
+		logrus.Debugf("failed to get the path to the konveyor executable. Error: %q", err)
+	}
+	pluginVersion, configDir, dataDir := "", "", ""
+	if p.Installed != nil {
+		pluginVersion = p.Installed.Version
+		if err := ensurePluginDirs(p.Name); err != nil {
+			logrus.Debugf("failed to create the directories of the plugin '%s'. Error: %q", p.Name, err)
+		}
+		configDir, dataDir = common.GetPluginConfigDir(p.Name), common.GetPluginDataDir(p.Name)
+	}
+	environment = setEnv(environment, types.ENV_BIN, konveyorBin, true)
+	environment = setEnv(environment, types.ENV_VERSION, lib.GetVersion(), true)
+	environment = setEnv(environment, types.ENV_HOME, common.GetStorageDir(), true)
+	environment = setEnv(environment, types.ENV_PLUGIN_NAME, p.Name, true)
+	environment = setEnv(environment, types.ENV_PLUGIN_VERSION, pluginVersion, true)
+	environment = setEnv(environment, types.ENV_PLUGIN_DIR, GetPluginDirectory(p), true)
+	environment = setEnv(environment, types.ENV_PLUGIN_CONFIG_DIR, configDir, true)
+	environment = setEnv(environment, types.ENV_PLUGIN_DATA_DIR, dataDir, true)
+	environment = setEnv(environment, types.ENV_LOG_LEVEL, logrus.GetLevel().String(), true)
+	environment = setEnv(environment, types.ENV_OUTPUT, output, true)
+	return environment
+}
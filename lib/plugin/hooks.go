@@ -0,0 +1,228 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HOOK_POST_INSTALL is run after the plugin is extracted.
+	HOOK_POST_INSTALL = "postInstall"
+	// HOOK_PRE_UNINSTALL is run before the plugin is removed.
+	HOOK_PRE_UNINSTALL = "preUninstall"
+	// HOOK_CHECK is run to verify that the plugin works.
+	HOOK_CHECK = "check"
+	// DEFAULT_HOOK_TIMEOUT is the maximum time the install and uninstall hooks are given to run if they don't specify a timeout.
+	DEFAULT_HOOK_TIMEOUT = 10 * time.Minute
+	// DEFAULT_CHECK_TIMEOUT is the maximum time the check hook is given to run if it doesn't specify a timeout.
+	DEFAULT_CHECK_TIMEOUT = time.Minute
+)
+
+// hookOptions control how the plugin hooks are run.
+var hookOptions = types.HookOptions{}
+
+// SetHookOptions sets the options used to run the plugin hooks.
+func SetHookOptions(options types.HookOptions) {
+	hookOptions = options
+}
+
+// getHook returns the hook with the given name. Returns nil if the plugin doesn't have the hook.
+func getHook(platform types.PluginVersionForPlatform, name string) *types.PluginHook {
+	switch name {
+	case HOOK_POST_INSTALL:
+		return platform.PostInstall
+	case HOOK_PRE_UNINSTALL:
+		return platform.PreUninstall
+	case HOOK_CHECK:
+		return platform.Check
+	}
+	return nil
+}
+
+// confirm asks the user a yes or no question. Returns the given error if konveyor is not running interactively.
+func confirm(prompt string, errNotInteractive error) (bool, error) {
+	if hookOptions.AssumeYes {
+		return true, nil
+	}
+	if !isTerminal(os.Stdin) {
+		return false, errNotInteractive
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read the answer. Error: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmHook asks the user whether the hook should be run.
+func confirmHook(pluginName, hookName string, hook types.PluginHook) (bool, error) {
+	prompt := fmt.Sprintf("The plugin '%s' wants to run the %s hook: %s\nRun it?", pluginName, hookName, strings.Join(hook.Command, " "))
+	return confirm(prompt, fmt.Errorf("the plugin '%s' has a %s hook but konveyor is not running interactively. Use --yes to run it or --no-hooks to skip it", pluginName, hookName))
+}
+
+// getHookTimeout returns the timeout for the hook.
+func getHookTimeout(hookName string, hook types.PluginHook) (time.Duration, error) {
+	if hook.Timeout != "" {
+		timeout, err := time.ParseDuration(hook.Timeout)
+		if err != nil {
+			return 0, fmt.Errorf("the timeout '%s' of the %s hook is invalid. Error: %w", hook.Timeout, hookName, err)
+		}
+		return timeout, nil
+	}
+	if hookName == HOOK_CHECK {
+		return DEFAULT_CHECK_TIMEOUT, nil
+	}
+	return DEFAULT_HOOK_TIMEOUT, nil
+}
+
+// GetHookLogPath returns the path to the file containing the output of the last run of the hook.
+// It is in the storage directory so that nothing is written into the source directory of linked plugins.
+func GetHookLogPath(installed types.InstalledPlugin, hookName string) string {
+	return filepath.Join(common.GetPluginDir(installed.Name), hookName+".log")
+}
+
+// runHook runs the hook of the installed plugin if it has one.
+// The install and uninstall hooks are only run if the user agrees to it and they can be disabled using the hook options.
+func runHook(installed types.InstalledPlugin, platform types.PluginVersionForPlatform, hookName string) error {
+	hook := getHook(platform, hookName)
+	if hook == nil {
+		return nil
+	}
+	if len(hook.Command) == 0 {
+		return fmt.Errorf("the %s hook of the plugin '%s' has no command", hookName, installed.Name)
+	}
+	if hookName != HOOK_CHECK {
+		if hookOptions.Disabled {
+			logrus.Infof("Skipping the %s hook of the plugin '%s'", hookName, installed.Name)
+			return nil
+		}
+		ok, err := confirmHook(installed.Name, hookName, *hook)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("the %s hook of the plugin '%s' was not approved. Use --no-hooks to skip it", hookName, installed.Name)
+		}
+	}
+	timeout, err := getHookTimeout(hookName, *hook)
+	if err != nil {
+		return err
+	}
+	pluginDir := GetInstalledPluginDir(installed)
+	command := hook.Command[0]
+	if strings.ContainsAny(command, `/\`) && !filepath.IsAbs(command) {
+		command = filepath.Join(pluginDir, command)
+	}
+	logPath := GetHookLogPath(installed, hookName)
+	if err := os.MkdirAll(filepath.Dir(logPath), types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the directory %s . Error: %w", filepath.Dir(logPath), err)
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create the log file at path %s . Error: %w", logPath, err)
+	}
+	defer logFile.Close()
+	logrus.Infof("Running the %s hook of the plugin '%s': %s", hookName, installed.Name, strings.Join(hook.Command, " "))
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	hookCmd := CommandContext(ctx, command, hook.Command[1:]...)
+	hookCmd.Dir = pluginDir
+	hookCmd.Env = GetPluginEnvironment(types.DiscoveredPlugin{Name: installed.Name, Path: GetPluginBinPath(installed), Installed: &installed}, "")
+	hookCmd.Stdout = io.MultiWriter(logFile, os.Stderr)
+	hookCmd.Stderr = io.MultiWriter(logFile, os.Stderr)
+	if err := hookCmd.Run(); err != nil {
+		// The hook succeeded but left a process running in the background that still has the output open.
+		if errors.Is(err, exec.ErrWaitDelay) && ctx.Err() == nil {
+			logrus.Debugf("the %s hook of the plugin '%s' left a process running in the background", hookName, installed.Name)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("the %s hook of the plugin '%s' did not finish within %s . The output is at %s", hookName, installed.Name, timeout, logPath)
+		}
+		return fmt.Errorf("the %s hook of the plugin '%s' failed. The output is at %s . Error: %w", hookName, installed.Name, logPath, err)
+	}
+	return nil
+}
+
+// GetInstalledPlatformMetadata returns the platform specific metadata of the installed version of the plugin.
+func GetInstalledPlatformMetadata(installed types.InstalledPlugin) (types.PluginVersionForPlatform, error) {
+	version, err := GetPluginVersionMetadata(installed)
+	if err != nil {
+		return types.PluginVersionForPlatform{}, err
+	}
+	platform, _, ok, err := selectPlatform(version, GetPlatformCandidates())
+	if err != nil {
+		return types.PluginVersionForPlatform{}, err
+	}
+	if !ok {
+		return types.PluginVersionForPlatform{}, fmt.Errorf("the version '%s' does not support our current platform", installed.Version)
+	}
+	return platform, nil
+}
+
+// CheckPlugin verifies that an installed plugin can be run.
+// It checks the executable (or the container image) and the requirements and then runs the check hook if the plugin has one.
+func CheckPlugin(name string) error {
+	installed, err := GetPluginFromLocalCache(name)
+	if err != nil {
+		return err
+	}
+	if installed.Image != "" {
+		if err := runContainerRuntime("image", "inspect", "--format", "{{.Id}}", installed.Image); err != nil {
+			return fmt.Errorf("failed to find the container image %s . Reinstall the plugin to pull it. Error: %w", installed.Image, err)
+		}
+	} else {
+		binPath := GetPluginBinPath(installed)
+		finfo, err := os.Stat(binPath)
+		if err != nil {
+			return fmt.Errorf("failed to find the plugin executable at path %s . Error: %w", binPath, err)
+		}
+		if finfo.IsDir() {
+			return fmt.Errorf("the plugin executable at path %s is a directory", binPath)
+		}
+		if installed.Interpreter != "" {
+			if _, err := CheckInterpreter(installed.Interpreter, true); err != nil {
+				return err
+			}
+		}
+	}
+	if err := CheckInstalledPluginRequirements(installed); err != nil {
+		return err
+	}
+	platform, err := GetInstalledPlatformMetadata(installed)
+	if err != nil {
+		logrus.Debugf("failed to get the metadata for the plugin '%s'. Skipping the %s hook. Error: %q", name, HOOK_CHECK, err)
+		return nil
+	}
+	return runHook(installed, platform, HOOK_CHECK)
+}
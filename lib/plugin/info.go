@@ -31,7 +31,7 @@ func getAllSupportedPlatforms(pluginMeta types.PluginMetadata) []string {
 	for _, version := range pluginMeta.Spec.Versions {
 		for _, platform := range version.Platforms {
 			os := platform.Selector.MatchLabels.Os
-			arch := platform.Selector.MatchLabels.Arch
+			arch := platform.Selector.MatchLabels.Arch + platform.Selector.MatchLabels.Variant
 			if os == "" && arch == "" {
 				// if both OS and Arch are not mentioned, then simply leave out the supported platforms
 				continue
@@ -42,7 +42,11 @@ func getAllSupportedPlatforms(pluginMeta types.PluginMetadata) []string {
 			if arch == "" {
 				arch = "*"
 			}
-			uniquePlatforms[common.GetPlatformAsSingleString(os, arch)] = true
+			name := common.GetPlatformAsSingleString(os, arch)
+			if libc := platform.Selector.MatchLabels.Libc; libc != "" {
+				name += "-" + libc
+			}
+			uniquePlatforms[name] = true
 		}
 	}
 	return common.Keys(uniquePlatforms)
@@ -54,8 +58,10 @@ func GetPluginInfo(name string) (string, error) {
 	pluginMeta, err := GetPluginMetadataFromLocalCache(name)
 	if err != nil {
 		logrus.Debugf("failed to get the plugin metadata from the local cache. Error: %q", err)
-		pluginMeta, err = GetPluginMetadataFromGithub(name)
-		if err != nil {
+		if inst, err := GetPluginFromLocalCache(name); err == nil && inst.Linked {
+			// linked plugins don't have any metadata
+			pluginMeta = types.PluginMetadata{Metadata: types.MetadataInfo{Name: name}}
+		} else if pluginMeta, err = GetPluginMetadataFromGithub(name); err != nil {
 			if types.IsNotFoundError(err) {
 				return "", fmt.Errorf("did not find a plugin named '%s' in the local cache or on Github", name)
 			}
@@ -69,10 +75,14 @@ func GetPluginInfo(name string) (string, error) {
 	}
 	installed := false
 	version := ""
+	linkedPath := ""
 	for _, inst := range localCache.Spec.Installed {
 		if inst.Name == name {
 			installed = true
 			version = inst.Version
+			if inst.Linked {
+				linkedPath = GetPluginBinPath(inst)
+			}
 			break
 		}
 	}
@@ -80,14 +90,26 @@ func GetPluginInfo(name string) (string, error) {
 	pluginInfo := types.PluginInfo{
 		Name:               pluginMeta.Metadata.Name,
 		Description:        pluginMeta.Spec.Description,
+		Aliases:            pluginMeta.Spec.Aliases,
 		HomePage:           pluginMeta.Spec.HomePage,
 		Documentation:      pluginMeta.Spec.Docs,
 		Tutorials:          pluginMeta.Spec.Tutorials,
 		Installed:          installed,
 		InstalledVersion:   version,
+		LinkedPath:         linkedPath,
 		VersionsAvailable:  common.Apply(func(v types.PluginVersionMetadata) string { return v.Version }, pluginMeta.Spec.Versions),
 		PlatformsSupported: getAllSupportedPlatforms(pluginMeta),
 	}
+	for _, v := range pluginMeta.Spec.Versions {
+		if version != "" && v.Version != version {
+			continue
+		}
+		if hasPermissions(v.Permissions) {
+			permissions := v.Permissions
+			pluginInfo.Permissions = &permissions
+		}
+		break
+	}
 	pluginInfoYaml, err := yaml.Marshal(pluginInfo)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal the plugin info to yaml. Error: %w", err)
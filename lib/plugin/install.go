@@ -21,42 +21,93 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"runtime"
+	"time"
 
 	"github.com/konveyor/cli/lib/cache"
 	"github.com/konveyor/cli/lib/common"
 	"github.com/konveyor/cli/lib/github"
+	"github.com/konveyor/cli/lib/stats"
 	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 )
 
 // InstallPlugin installs a plugin given the the plugin metadata.
+// Any plugins that it depends on are also installed.
 func InstallPlugin(plugin types.PluginMetadata) error {
+	return installPlugin(plugin, "", map[string]bool{})
+}
+
+// installPlugin installs a version of the plugin that satisfies the constraint.
+// installing contains the plugins being installed in order to break dependency cycles.
+func installPlugin(plugin types.PluginMetadata, constraint string, installing map[string]bool) error {
+	if err := ValidatePluginName(plugin.Metadata.Name); err != nil {
+		return err
+	}
+	installing[plugin.Metadata.Name] = true
 	pluginDir := common.GetPluginDir(plugin.Metadata.Name)
 	if len(plugin.Spec.Versions) == 0 {
 		return fmt.Errorf("no versions are listed for the plugin")
 	}
-	version, platform, err := SelectProperVersionAndPlatform(plugin)
+	version, platform, selectedPlatform, err := selectVersionAndPlatform(plugin, constraint)
 	if err != nil {
 		return err
 	}
+	if platform.Image == "" && !isSafeRelativePath(platform.Bin) {
+		return fmt.Errorf("the bin '%s' must be a relative path inside the plugin archive", platform.Bin)
+	}
 	logrus.Infof("Found a version of the plugin that supports our current platform: %s", version.Version)
-	outputDir := filepath.Join(pluginDir, version.Version, runtime.GOOS+"-"+runtime.GOARCH)
+	if err := approvePermissions(plugin.Metadata.Name, version); err != nil {
+		return err
+	}
+	if err := checkBinaries(version.Requires); err != nil {
+		return err
+	}
+	if platform.Interpreter != "" && platform.Image == "" {
+		if _, err := CheckInterpreter(platform.Interpreter, true); err != nil {
+			return err
+		}
+	}
+	if err := installDependencies(plugin.Metadata.Name, version.Requires, installing); err != nil {
+		return fmt.Errorf("failed to install the plugins required by the plugin '%s'. Error: %w", plugin.Metadata.Name, err)
+	}
+	platformName := common.GetPlatformAsSingleString(selectedPlatform.Os, selectedPlatform.Arch)
+	outputDir := filepath.Join(pluginDir, version.Version, platformName)
 	if err := os.MkdirAll(outputDir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
 		return fmt.Errorf("failed to make the directory %s for storing the plugins. Error: %w", outputDir, err)
 	}
-	outputPath := filepath.Join(outputDir, plugin.Metadata.Name+".tar.gz")
-	logrus.Infof("Downloading the plugin from the URL: %s", platform.Uri)
-	if err := github.Download(platform.Uri, outputPath, platform.Sha256); err != nil {
-		return fmt.Errorf("failed to download the plugin named '%s'. Error: %w", plugin.Metadata.Name, err)
+	if platform.Image != "" {
+		if err := PullImage(platform.Image); err != nil {
+			return fmt.Errorf("failed to pull the container image for the plugin named '%s'. Error: %w", plugin.Metadata.Name, err)
+		}
+	} else if srcDir, ok := getLocalDirectory(platform.Uri); ok {
+		logrus.Infof("Copying the plugin from the directory: %s", srcDir)
+		if platform.Sha256 != "" {
+			logrus.Warnf("The checksum cannot be verified for a directory. Ignoring.")
+		}
+		if err := github.CopyDir(srcDir, outputDir); err != nil {
+			return fmt.Errorf("failed to copy the plugin named '%s' from the directory %s . Error: %w", plugin.Metadata.Name, srcDir, err)
+		}
+		logrus.Info("Copy complete.")
+	} else {
+		outputPath := filepath.Join(outputDir, plugin.Metadata.Name+".tar.gz")
+		logrus.Infof("Downloading the plugin from the URL: %s", platform.Uri)
+		if err := github.Download(platform.Uri, outputPath, platform.Sha256); err != nil {
+			return fmt.Errorf("failed to download the plugin named '%s'. Error: %w", plugin.Metadata.Name, err)
+		}
+		logrus.Info("Download complete.")
+		logrus.Info("Expanding the plugin archive.")
+		if err := github.ExtractTarGz(outputPath); err != nil {
+			return fmt.Errorf("failed to extract the plugin archive at path %s . Error: %w", outputPath, err)
+		}
+		logrus.Info("Done expanding the archive.")
 	}
-	logrus.Info("Download complete.")
-	logrus.Info("Expanding the plugin archive.")
-	if err := github.ExtractTarGz(outputPath); err != nil {
-		return fmt.Errorf("failed to extract the plugin archive at path %s . Error: %w", outputPath, err)
+	if platform.Image == "" {
+		binPath := filepath.Join(outputDir, platform.Bin)
+		if _, err := os.Stat(binPath); err != nil {
+			return fmt.Errorf("failed to find the plugin executable at path %s . Error: %w", binPath, err)
+		}
 	}
-	logrus.Info("Done expanding the archive.")
 	pluginYaml, err := yaml.Marshal(plugin)
 	if err != nil {
 		return fmt.Errorf("failed to marshal the plugin metadata to yaml. Error: %w", err)
@@ -65,24 +116,79 @@ func InstallPlugin(plugin types.PluginMetadata) error {
 	if err := ioutil.WriteFile(pluginYamlPath, pluginYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
 		return fmt.Errorf("failed to write the plugin YAML to the path %s . Error: %w", pluginYamlPath, err)
 	}
+	installed := types.InstalledPlugin{
+		Name:        plugin.Metadata.Name,
+		Version:     version.Version,
+		Platform:    platformName,
+		Bin:         platform.Bin,
+		Image:       platform.Image,
+		InstalledAt: time.Now().UTC(),
+	}
+	if platform.Image == "" {
+		installed.Interpreter = platform.Interpreter
+	}
+	if err := runHook(installed, platform, HOOK_POST_INSTALL); err != nil {
+		return err
+	}
 	localCache, err := cache.GetLocalCache()
 	if err != nil {
 		return fmt.Errorf("failed to get the local cache. Error: %w", err)
 	}
-	localCache.Spec.Installed = append(localCache.Spec.Installed, types.InstalledPlugin{
-		Name:     plugin.Metadata.Name,
-		Version:  version.Version,
-		Platform: common.GetPlatformAsSingleString(runtime.GOOS, runtime.GOARCH),
-		Bin:      platform.Bin,
-	})
+	localCache.Spec.Installed = append(localCache.Spec.Installed, installed)
 	if err := cache.SaveLocalCache(localCache); err != nil {
 		return fmt.Errorf("failed to save the local cache. Error: %w", err)
 	}
+	warnAliasCollisions(plugin.Metadata.Name, plugin.Spec.Aliases)
+	cachePluginDescriptionOnInstall(installed)
+	return nil
+}
+
+// getLocalDirectory returns the path if the uri refers to a local directory instead of an archive.
+func getLocalDirectory(uri string) (string, bool) {
+	if github.IsURL(uri) {
+		return "", false
+	}
+	path := github.GetLocalPath(uri)
+	finfo, err := os.Stat(path)
+	if err != nil || !finfo.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// installDependencies installs the plugins that are required and not yet installed.
+func installDependencies(name string, requires types.PluginRequirements, installing map[string]bool) error {
+	for _, dep := range requires.Plugins {
+		if installing[dep.Name] {
+			continue
+		}
+		if _, err := GetPluginFromLocalCache(dep.Name); err == nil {
+			if err := checkPluginDependency(dep); err != nil {
+				return err
+			}
+			continue
+		}
+		logrus.Infof("The plugin '%s' requires the plugin '%s'. Looking for it on Github.", name, dep.Name)
+		depMeta, err := GetPluginMetadataFromGithub(dep.Name)
+		if err != nil {
+			if types.IsNotFoundError(err) {
+				return fmt.Errorf("did not find a plugin named '%s' on Github", dep.Name)
+			}
+			return fmt.Errorf("failed to get the plugin '%s' from the Github repo. Error: %w", dep.Name, err)
+		}
+		if err := installPlugin(depMeta, dep.Version, installing); err != nil {
+			return fmt.Errorf("failed to install the plugin '%s'. Error: %w", dep.Name, err)
+		}
+		logrus.Infof("The plugin named '%s' was installed!", dep.Name)
+	}
 	return nil
 }
 
 // InstallPluginFromGithub downloads and installs a plugin from the Github repo.
 func InstallPluginFromGithub(name string) error {
+	if err := ValidatePluginName(name); err != nil {
+		return err
+	}
 	if _, err := GetPluginFromLocalCache(name); err == nil {
 		return types.ErrPluginAlreadyInstalled
 	}
@@ -97,8 +203,24 @@ func InstallPluginFromGithub(name string) error {
 }
 
 // UninstallPlugin uninstalls an installed plugin.
+// Linked plugins are only removed from the local cache, the directory they point at is never deleted.
 func UninstallPlugin(name string) error {
-	if _, err := GetPluginFromLocalCache(name); err != nil {
+	installed, err := GetPluginFromLocalCache(name)
+	if err != nil {
+		return err
+	}
+	if installed.Linked {
+		return UnlinkPlugin(name)
+	}
+	pluginsDir := filepath.Join(common.GetStorageDir(), types.PLUGINS_DIR)
+	pluginDir := common.GetPluginDir(name)
+	// Plugins installed with an invalid name by older versions are only removed from the local cache.
+	safe := isInsideDir(pluginsDir, pluginDir)
+	if !safe {
+		logrus.Warnf("The plugin name '%s' is invalid. Only removing it from the local cache, its files are not deleted.", name)
+	} else if platform, err := GetInstalledPlatformMetadata(installed); err != nil {
+		logrus.Debugf("failed to get the metadata for the plugin '%s'. Skipping the %s hook. Error: %q", name, HOOK_PRE_UNINSTALL, err)
+	} else if err := runHook(installed, platform, HOOK_PRE_UNINSTALL); err != nil {
 		return err
 	}
 	localCache, err := cache.GetLocalCache()
@@ -109,7 +231,10 @@ func UninstallPlugin(name string) error {
 	if err := cache.SaveLocalCache(localCache); err != nil {
 		return fmt.Errorf("failed to save the local cache. Error: %w", err)
 	}
-	return os.RemoveAll(common.GetPluginDir(name))
+	if !safe {
+		return nil
+	}
+	return removeAllInside(pluginsDir, pluginDir)
 }
 
 // UninstallBrokenPlugins uninstalls any broken plugins not mentioned in the cache.
@@ -118,6 +243,14 @@ func UninstallBrokenPlugins() error {
 	if err != nil {
 		return fmt.Errorf("failed to get the local cache. Error: %w", err)
 	}
+	for _, installed := range localCache.Spec.Installed {
+		if !installed.Linked {
+			continue
+		}
+		if _, err := os.Stat(GetPluginBinPath(installed)); err != nil {
+			logrus.Warnf("The linked plugin '%s' points at a missing executable %s . Use unlink to remove it.", installed.Name, GetPluginBinPath(installed))
+		}
+	}
 	pluginsDir := filepath.Join(common.GetStorageDir(), types.PLUGINS_DIR)
 	fs, err := os.ReadDir(pluginsDir)
 	if err != nil {
@@ -148,16 +281,82 @@ func UninstallBrokenPlugins() error {
 	return nil
 }
 
+// getInstalledTime returns when the plugin was installed.
+// Plugins installed by older versions of konveyor use the time their directory was last modified.
+func getInstalledTime(installed types.InstalledPlugin) time.Time {
+	if !installed.InstalledAt.IsZero() {
+		return installed.InstalledAt
+	}
+	finfo, err := os.Stat(common.GetPluginDir(installed.Name))
+	if err != nil {
+		return time.Time{}
+	}
+	return finfo.ModTime()
+}
+
+// GetUnusedPlugins returns the installed plugins that have not been run since the given time along with their usage statistics.
+// Plugins that were never run are only returned if they were installed before that time.
+func GetUnusedPlugins(since time.Time) ([]types.InstalledPlugin, map[string]types.PluginStats, error) {
+	localCache, err := cache.GetLocalCache()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the local cache. Error: %w", err)
+	}
+	pluginStats, err := stats.GetPluginStats()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the usage statistics of the plugins. Error: %w", err)
+	}
+	unused := []types.InstalledPlugin{}
+	for _, installed := range localCache.Spec.Installed {
+		lastUsed := pluginStats[installed.Name].LastUsed
+		if lastUsed.IsZero() {
+			lastUsed = getInstalledTime(installed)
+		}
+		if lastUsed.Before(since) {
+			unused = append(unused, installed)
+		}
+	}
+	return unused, pluginStats, nil
+}
+
 // SelectProperVersionAndPlatform selects an appropriate version and platform for the plugin.
-func SelectProperVersionAndPlatform(plugin types.PluginMetadata) (types.PluginVersionMetadata, types.PluginVersionForPlatform, error) {
+// It also returns the platform that was selected, which may differ from the current platform if a fallback was used.
+func SelectProperVersionAndPlatform(plugin types.PluginMetadata) (types.PluginVersionMetadata, types.PluginVersionForPlatform, types.Platform, error) {
+	return selectVersionAndPlatform(plugin, "")
+}
+
+// selectVersionAndPlatform selects the first version that satisfies the constraint and supports our current platform.
+// Versions that require a different version of konveyor are skipped.
+func selectVersionAndPlatform(plugin types.PluginMetadata, constraint string) (types.PluginVersionMetadata, types.PluginVersionForPlatform, types.Platform, error) {
+	candidates := GetPlatformCandidates()
 	for _, version := range plugin.Spec.Versions {
-		for _, platform := range version.Platforms {
-			if (platform.Selector.MatchLabels.Os == "" || platform.Selector.MatchLabels.Os == runtime.GOOS) &&
-				(platform.Selector.MatchLabels.Arch == "" || platform.Selector.MatchLabels.Arch == runtime.GOARCH) {
-				return version, platform, nil
+		if constraint != "" {
+			ok, err := common.SatisfiesConstraint(version.Version, constraint)
+			if err != nil {
+				return types.PluginVersionMetadata{}, types.PluginVersionForPlatform{}, types.Platform{}, err
+			}
+			if !ok {
+				logrus.Debugf("The version '%s' does not satisfy the constraint '%s'. Trying next version.", version.Version, constraint)
+				continue
 			}
 		}
+		if err := checkKonveyorVersion(version.Requires); err != nil {
+			logrus.Warnf("The version '%s' cannot be used. Trying next version. Error: %q", version.Version, err)
+			continue
+		}
+		platform, candidate, ok, err := selectPlatform(version, candidates)
+		if err != nil {
+			return types.PluginVersionMetadata{}, types.PluginVersionForPlatform{}, types.Platform{}, err
+		}
+		if ok {
+			if candidate.Warning != "" {
+				logrus.Warn(candidate.Warning)
+			}
+			return version, platform, candidate.Platform, nil
+		}
 		logrus.Warnf("The version '%s' does not support our current platform. Trying next version.", version.Version)
 	}
-	return types.PluginVersionMetadata{}, types.PluginVersionForPlatform{}, fmt.Errorf("the plugin has no version that supports our current platform")
+	if constraint != "" {
+		return types.PluginVersionMetadata{}, types.PluginVersionForPlatform{}, types.Platform{}, fmt.Errorf("the plugin has no version that satisfies the constraint '%s' and supports our current platform", constraint)
+	}
+	return types.PluginVersionMetadata{}, types.PluginVersionForPlatform{}, types.Platform{}, fmt.Errorf("the plugin has no version that supports our current platform")
 }
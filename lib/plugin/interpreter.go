@@ -0,0 +1,93 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+)
+
+const (
+	// INTERPRETER_VERSION_TIMEOUT is the maximum time an interpreter is given to print its version.
+	INTERPRETER_VERSION_TIMEOUT = 10 * time.Second
+)
+
+var interpreterVersionPattern = regexp.MustCompile(`\d+(\.\d+){0,2}`)
+
+// parseInterpreter splits the interpreter into the executable and the version constraint.
+// Example: "python3 >=3.8" becomes python3 and >=3.8
+func parseInterpreter(interpreter string) (string, string) {
+	fields := strings.Fields(interpreter)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	return fields[0], strings.Join(fields[1:], " ")
+}
+
+// getInterpreterVersion runs the interpreter with --version and returns the first version found in the output.
+// Example: 3.10.12 for "Python 3.10.12" and 18.1.0 for "v18.1.0"
+func getInterpreterVersion(interpreterPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), INTERPRETER_VERSION_TIMEOUT)
+	defer cancel()
+	output, err := CommandContext(ctx, interpreterPath, "--version").CombinedOutput()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("the interpreter did not print its version within %s", INTERPRETER_VERSION_TIMEOUT)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run '%s --version'. Error: %w", interpreterPath, err)
+	}
+	version := interpreterVersionPattern.FindString(string(output))
+	if version == "" {
+		return "", fmt.Errorf("failed to find a version in the output of '%s --version': %s", interpreterPath, strings.TrimSpace(string(output)))
+	}
+	return version, nil
+}
+
+// CheckInterpreter checks that the interpreter is on the PATH and returns the path to it.
+// If checkVersion is true the version of the interpreter must also satisfy the constraint, if there is one.
+func CheckInterpreter(interpreter string, checkVersion bool) (string, error) {
+	name, constraint := parseInterpreter(interpreter)
+	if name == "" {
+		return "", fmt.Errorf("the interpreter is empty")
+	}
+	interpreterPath, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%w : the interpreter '%s' must be installed and on the PATH", types.ErrUnmetRequirements, name)
+	}
+	if !checkVersion || constraint == "" {
+		return interpreterPath, nil
+	}
+	version, err := getInterpreterVersion(interpreterPath)
+	if err != nil {
+		return interpreterPath, fmt.Errorf("failed to get the version of the interpreter '%s'. Error: %w", name, err)
+	}
+	ok, err := common.SatisfiesConstraint(version, constraint)
+	if err != nil {
+		return interpreterPath, fmt.Errorf("failed to check the version of the interpreter '%s'. Error: %w", name, err)
+	}
+	if !ok {
+		return interpreterPath, fmt.Errorf("%w : the interpreter '%s' %s is required but the installed version is %s", types.ErrUnmetRequirements, name, constraint, version)
+	}
+	return interpreterPath, nil
+}
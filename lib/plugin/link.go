@@ -0,0 +1,134 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/konveyor/cli/lib/cache"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// LinkPlugin registers a development plugin that runs the executable at the given path.
+// The path can be the executable itself or a directory containing it, in which case bin is the path to the executable inside the directory.
+// If bin is empty the executable is assumed to be named after the plugin.
+// If interpreter is not empty the executable is a script that is run using the interpreter.
+func LinkPlugin(name, version, path, bin, interpreter string) (types.InstalledPlugin, error) {
+	if err := ValidatePluginName(name); err != nil {
+		return types.InstalledPlugin{}, err
+	}
+	if _, err := GetPluginFromLocalCache(name); err == nil {
+		return types.InstalledPlugin{}, types.ErrPluginAlreadyInstalled
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return types.InstalledPlugin{}, fmt.Errorf("failed to make the path %s absolute. Error: %w", path, err)
+	}
+	finfo, err := os.Stat(absPath)
+	if err != nil {
+		return types.InstalledPlugin{}, fmt.Errorf("failed to find the plugin at path %s . Error: %w", absPath, err)
+	}
+	linkPath := absPath
+	if finfo.IsDir() {
+		if bin != "" && !isSafeRelativePath(bin) {
+			return types.InstalledPlugin{}, fmt.Errorf("the bin '%s' must be a relative path inside the directory", bin)
+		}
+		if bin == "" {
+			bin = types.VALID_PLUGIN_FILENAME_PREFIX + name
+			if runtime.GOOS == "windows" {
+				bin += ".exe"
+			}
+		}
+	} else {
+		if bin != "" {
+			return types.InstalledPlugin{}, fmt.Errorf("the path to the executable can only be specified when linking a directory")
+		}
+		linkPath, bin = filepath.Split(absPath)
+		linkPath = filepath.Clean(linkPath)
+	}
+	binPath := filepath.Join(linkPath, bin)
+	binInfo, err := os.Stat(binPath)
+	if err != nil {
+		return types.InstalledPlugin{}, fmt.Errorf("failed to find the plugin executable at path %s . Error: %w", binPath, err)
+	}
+	if interpreter != "" {
+		if _, err := CheckInterpreter(interpreter, true); err != nil {
+			return types.InstalledPlugin{}, err
+		}
+	} else if runtime.GOOS != "windows" && !isExecutable(binInfo.Mode()) {
+		return types.InstalledPlugin{}, fmt.Errorf("the file at path %s is not executable", binPath)
+	}
+	installed := types.InstalledPlugin{
+		Name:        name,
+		Version:     version,
+		Platform:    common.GetPlatformAsSingleString(runtime.GOOS, runtime.GOARCH),
+		Bin:         bin,
+		Linked:      true,
+		LinkPath:    linkPath,
+		Interpreter: interpreter,
+		InstalledAt: time.Now().UTC(),
+	}
+	localCache, err := cache.GetLocalCache()
+	if err != nil {
+		return installed, fmt.Errorf("failed to get the local cache. Error: %w", err)
+	}
+	localCache.Spec.Installed = append(localCache.Spec.Installed, installed)
+	if err := cache.SaveLocalCache(localCache); err != nil {
+		return installed, fmt.Errorf("failed to save the local cache. Error: %w", err)
+	}
+	cachePluginDescriptionOnInstall(installed)
+	return installed, nil
+}
+
+// UnlinkPlugin removes a linked plugin from the local cache without touching the directory it points at.
+func UnlinkPlugin(name string) error {
+	installed, err := GetPluginFromLocalCache(name)
+	if err != nil {
+		return err
+	}
+	if !installed.Linked {
+		return errors.New("the plugin is not linked. Use uninstall instead")
+	}
+	localCache, err := cache.GetLocalCache()
+	if err != nil {
+		return fmt.Errorf("failed to get the local cache. Error: %w", err)
+	}
+	localCache.Spec.Installed = common.Filter(func(p types.InstalledPlugin) bool { return p.Name != name }, localCache.Spec.Installed)
+	if err := cache.SaveLocalCache(localCache); err != nil {
+		return fmt.Errorf("failed to save the local cache. Error: %w", err)
+	}
+	if err := os.Remove(getPluginDescriptionPath(name)); err != nil && !os.IsNotExist(err) {
+		logrus.Debugf("failed to remove the description of the plugin '%s'. Error: %q", name, err)
+	}
+	for _, hookName := range []string{HOOK_POST_INSTALL, HOOK_PRE_UNINSTALL, HOOK_CHECK} {
+		if err := os.Remove(GetHookLogPath(installed, hookName)); err != nil && !os.IsNotExist(err) {
+			logrus.Debugf("failed to remove the %s hook log of the plugin '%s'. Error: %q", hookName, name, err)
+		}
+	}
+	// Only removed if it is empty.
+	if isInsideDir(filepath.Join(common.GetStorageDir(), types.PLUGINS_DIR), common.GetPluginDir(name)) {
+		_ = os.Remove(common.GetPluginDir(name))
+	}
+	return nil
+}
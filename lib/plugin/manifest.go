@@ -0,0 +1,251 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/cli/lib/github"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	assetPatternToken = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}|\*`)
+	armVariantArch    = regexp.MustCompile(`^arm(v[5-7])$`)
+)
+
+// compileAssetPattern converts a pattern like *-{{os}}-{{arch}}.tar.gz into a regular expression.
+// The supported placeholders are {{os}}, {{arch}}, {{version}} and {{name}}. A * matches anything.
+func compileAssetPattern(pattern, name, version string) (*regexp.Regexp, error) {
+	expr := &strings.Builder{}
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range assetPatternToken.FindAllStringSubmatchIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		last = loc[1]
+		if loc[2] == -1 {
+			expr.WriteString(".*")
+			continue
+		}
+		switch placeholder := pattern[loc[2]:loc[3]]; placeholder {
+		case "os":
+			expr.WriteString(`(?P<os>[a-z0-9]+)`)
+		case "arch":
+			expr.WriteString(`(?P<arch>[a-z0-9]+)`)
+		case "version":
+			expr.WriteString(regexp.QuoteMeta(version))
+		case "name":
+			expr.WriteString(regexp.QuoteMeta(name))
+		default:
+			return nil, fmt.Errorf("the placeholder '{{%s}}' is invalid. Valid placeholders are {{os}}, {{arch}}, {{version}} and {{name}}", placeholder)
+		}
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile the asset pattern '%s' . Error: %w", pattern, err)
+	}
+	if re.SubexpIndex("os") == -1 || re.SubexpIndex("arch") == -1 {
+		return nil, fmt.Errorf("the asset pattern '%s' must contain both {{os}} and {{arch}}", pattern)
+	}
+	return re, nil
+}
+
+// getAssetSha256 returns the checksum of the asset, using the checksum file published along with it if there is one.
+func getAssetSha256(asset types.ReleaseAsset, assets map[string]types.ReleaseAsset) (string, error) {
+	if checkSumAsset, ok := assets[asset.Name+types.CHECKSUM_FILE_SUFFIX]; ok {
+		content, err := github.GetContents(checkSumAsset.Uri)
+		if err != nil {
+			return "", err
+		}
+		fields := strings.Fields(string(content))
+		if len(fields) == 0 || !validSha256.MatchString(fields[0]) {
+			return "", fmt.Errorf("the checksum file %s does not contain a valid sha256 checksum", checkSumAsset.Name)
+		}
+		return fields[0], nil
+	}
+	logrus.Infof("Computing the checksum of %s", asset.Name)
+	return github.GetSha256(asset.Uri)
+}
+
+// GeneratePluginVersion creates the metadata for a version of a plugin from the release assets that match the pattern.
+// The os and arch of each platform are inferred from the asset name. The bin can contain the placeholders {{os}}, {{arch}} and {{exe}}.
+// If baseUrl is not empty the uris point at baseUrl/<asset name> instead of where the assets were found.
+func GeneratePluginVersion(name, version string, assets []types.ReleaseAsset, assetPattern, bin, baseUrl string) (types.PluginVersionMetadata, error) {
+	pluginVersion := types.PluginVersionMetadata{Version: version}
+	re, err := compileAssetPattern(assetPattern, name, version)
+	if err != nil {
+		return pluginVersion, err
+	}
+	assetsByName := map[string]types.ReleaseAsset{}
+	for _, asset := range assets {
+		assetsByName[asset.Name] = asset
+	}
+	seen := map[types.MatchLabels]string{}
+	for _, asset := range assets {
+		matches := re.FindStringSubmatch(asset.Name)
+		if matches == nil || strings.HasSuffix(asset.Name, types.CHECKSUM_FILE_SUFFIX) {
+			logrus.Debugf("The asset %s does not match the pattern. Skipping.", asset.Name)
+			continue
+		}
+		labels := types.MatchLabels{Os: matches[re.SubexpIndex("os")], Arch: matches[re.SubexpIndex("arch")]}
+		if variant := armVariantArch.FindStringSubmatch(labels.Arch); variant != nil {
+			labels.Arch, labels.Variant = "arm", variant[1]
+		}
+		if other, ok := seen[labels]; ok {
+			return pluginVersion, fmt.Errorf("the assets %s and %s are both for the platform %s . Use a more specific asset pattern", other, asset.Name, getPlatformLabelsString(labels))
+		}
+		seen[labels] = asset.Name
+		sha256, err := getAssetSha256(asset, assetsByName)
+		if err != nil {
+			return pluginVersion, fmt.Errorf("failed to get the checksum of the asset %s . Error: %w", asset.Name, err)
+		}
+		uri := asset.Uri
+		if baseUrl != "" {
+			uri = strings.TrimSuffix(baseUrl, "/") + "/" + asset.Name
+		}
+		exe := ""
+		if labels.Os == "windows" {
+			exe = ".exe"
+		}
+		pluginVersion.Platforms = append(pluginVersion.Platforms, types.PluginVersionForPlatform{
+			Selector: types.Selector{MatchLabels: labels},
+			Uri:      uri,
+			Sha256:   sha256,
+			Bin:      strings.NewReplacer("{{os}}", labels.Os, "{{arch}}", labels.Arch, "{{exe}}", exe).Replace(bin),
+		})
+		logrus.Infof("Found the asset %s for the platform %s", asset.Name, getPlatformLabelsString(labels))
+	}
+	if len(pluginVersion.Platforms) == 0 {
+		return pluginVersion, fmt.Errorf("none of the %d assets match the pattern '%s'", len(assets), assetPattern)
+	}
+	sort.Slice(pluginVersion.Platforms, func(i, j int) bool {
+		a, b := pluginVersion.Platforms[i].Selector.MatchLabels, pluginVersion.Platforms[j].Selector.MatchLabels
+		if a.Os != b.Os {
+			return a.Os < b.Os
+		}
+		if a.Arch != b.Arch {
+			return a.Arch < b.Arch
+		}
+		return a.Variant < b.Variant
+	})
+	return pluginVersion, nil
+}
+
+// getPlatformLabelsString returns the platform in the form os/arch or os/arch/variant.
+func getPlatformLabelsString(labels types.MatchLabels) string {
+	platform := labels.Os + "/" + labels.Arch
+	if labels.Variant != "" {
+		platform += "/" + labels.Variant
+	}
+	return platform
+}
+
+// GetLocalReleaseAssets returns the files in a directory of release artifacts. The uris are absolute paths.
+func GetLocalReleaseAssets(dir string) ([]types.ReleaseAsset, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make the path %s absolute. Error: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the directory %s . Error: %w", dir, err)
+	}
+	assets := []types.ReleaseAsset{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		assets = append(assets, types.ReleaseAsset{Name: entry.Name(), Uri: filepath.Join(dir, entry.Name())})
+	}
+	return assets, nil
+}
+
+// NewPluginManifest returns a plugin YAML containing a single version.
+func NewPluginManifest(name string, version types.PluginVersionMetadata) ([]byte, error) {
+	plugin := types.PluginMetadata{
+		ApiVersion: types.API_VERSION,
+		Kind:       types.PLUGIN_FILE_KIND,
+		Metadata:   types.MetadataInfo{Name: name},
+		Spec:       types.PluginMetadataSpec{Versions: []types.PluginVersionMetadata{version}},
+	}
+	return marshalManifest(plugin)
+}
+
+// AddVersionToManifest prepends the version to the versions in the plugin YAML.
+// The rest of the YAML, including comments, is preserved.
+func AddVersionToManifest(pluginYaml []byte, version types.PluginVersionMetadata) ([]byte, error) {
+	plugin := types.PluginMetadata{}
+	if err := yaml.Unmarshal(pluginYaml, &plugin); err != nil {
+		return nil, fmt.Errorf("failed to parse the plugin yaml. Error: %w", err)
+	}
+	for _, v := range plugin.Spec.Versions {
+		if v.Version == version.Version {
+			return nil, fmt.Errorf("the version '%s' is already in the plugin yaml", version.Version)
+		}
+	}
+	doc := yaml.Node{}
+	if err := yaml.Unmarshal(pluginYaml, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the plugin yaml. Error: %w", err)
+	}
+	versions := getMappingValue(getMappingValue(doc.Content[0], "spec"), "versions")
+	if versions == nil || versions.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("the plugin yaml does not contain a list of versions at spec.versions")
+	}
+	versionNode := yaml.Node{}
+	if err := versionNode.Encode(version); err != nil {
+		return nil, fmt.Errorf("failed to encode the version. Error: %w", err)
+	}
+	versions.Content = append([]*yaml.Node{&versionNode}, versions.Content...)
+	return marshalManifest(&doc)
+}
+
+// getMappingValue returns the value of the key in a YAML mapping. Returns nil if the key doesn't exist.
+func getMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// marshalManifest marshals the plugin YAML using the same indentation as the plugin YAMLs in the plugins repo.
+func marshalManifest(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal the plugin yaml. Error: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal the plugin yaml. Error: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,148 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+)
+
+// pluginOS is the part of the operating system used to find the plugins on the PATH.
+// It can be replaced to find the plugins the way another operating system would.
+type pluginOS interface {
+	// GOOS returns the name of the operating system. Example: linux, windows
+	GOOS() string
+	Getenv(key string) string
+	ReadDir(dir string) ([]os.FileInfo, error)
+	EvalSymlinks(path string) (string, error)
+}
+
+// hostOS is the operating system konveyor is running on.
+type hostOS struct{}
+
+func (hostOS) GOOS() string { return runtime.GOOS }
+
+func (hostOS) Getenv(key string) string { return os.Getenv(key) }
+
+func (hostOS) ReadDir(dir string) ([]os.FileInfo, error) { return ioutil.ReadDir(dir) }
+
+func (hostOS) EvalSymlinks(path string) (string, error) { return filepath.EvalSymlinks(path) }
+
+// currentOS is used to find the plugins on the PATH.
+var currentOS pluginOS = hostOS{}
+
+// isWindows returns true if the plugins are found the way Windows finds executables.
+func isWindows(pos pluginOS) bool {
+	return pos.GOOS() == "windows"
+}
+
+// splitPathList splits the PATH into its directories using the list separator of the operating system.
+func splitPathList(pos pluginOS, envPath string) []string {
+	if envPath == "" {
+		return []string{}
+	}
+	if !isWindows(pos) {
+		return strings.Split(envPath, ":")
+	}
+	// Directories on Windows can be quoted. Example: "C:\Program Files\Konveyor"
+	return common.Apply(func(dir string) string { return strings.Trim(dir, `"`) }, strings.Split(envPath, ";"))
+}
+
+// getExecutableExtensions returns the lowercase extensions of executable files from PATHEXT, in order of precedence.
+// Only Windows uses extensions to find executables so it returns nil on other operating systems.
+func getExecutableExtensions(pos pluginOS) []string {
+	if !isWindows(pos) {
+		return nil
+	}
+	pathExt := pos.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = types.WINDOWS_DEFAULT_PATHEXT
+	}
+	exts := []string{}
+	for _, ext := range strings.Split(strings.ToLower(pathExt), ";") {
+		ext = strings.TrimSpace(ext)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if !common.Contains(ext, exts) {
+			exts = append(exts, ext)
+		}
+	}
+	return exts
+}
+
+// getExecutableExtensionIndex returns the position of the extension of the filename in PATHEXT. Returns -1 if it is not executable.
+func getExecutableExtensionIndex(exts []string, filename string) int {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return -1
+	}
+	return common.FindIndex(func(e string) bool { return e == ext }, exts)
+}
+
+// trimExecutableExtension removes the executable extension from the filename on Windows.
+// Example: konveyor-foo.exe becomes konveyor-foo
+// It returns false if the filename doesn't have one of the extensions in PATHEXT.
+func trimExecutableExtension(pos pluginOS, filename string) (string, bool) {
+	if getExecutableExtensionIndex(getExecutableExtensions(pos), filename) == -1 {
+		return filename, false
+	}
+	return strings.TrimSuffix(filename, filepath.Ext(filename)), true
+}
+
+// hasPluginPrefix returns true if the filename starts with the plugin prefix. Windows filenames are case insensitive.
+func hasPluginPrefix(pos pluginOS, filename string) bool {
+	if isWindows(pos) {
+		filename = strings.ToLower(filename)
+	}
+	return strings.HasPrefix(filename, types.VALID_PLUGIN_FILENAME_PREFIX)
+}
+
+// isPluginExecutable returns true if the file can be run as a plugin.
+// Windows uses the extension of the file while other operating systems use its permissions.
+func isPluginExecutable(pos pluginOS, f os.FileInfo) bool {
+	if isWindows(pos) {
+		_, ok := trimExecutableExtension(pos, f.Name())
+		return ok
+	}
+	return isExecutable(f.Mode())
+}
+
+// sortByExecutableExtension sorts the files so that, for files with the same name, the one
+// whose extension comes first in PATHEXT comes first. Example: konveyor-foo.exe before konveyor-foo.bat
+func sortByExecutableExtension(pos pluginOS, files []os.FileInfo) {
+	exts := getExecutableExtensions(pos)
+	sort.SliceStable(files, func(i, j int) bool {
+		stemI := strings.ToLower(strings.TrimSuffix(files[i].Name(), filepath.Ext(files[i].Name())))
+		stemJ := strings.ToLower(strings.TrimSuffix(files[j].Name(), filepath.Ext(files[j].Name())))
+		if stemI != stemJ {
+			return stemI < stemJ
+		}
+		idxI, idxJ := getExecutableExtensionIndex(exts, files[i].Name()), getExecutableExtensionIndex(exts, files[j].Name())
+		return idxI != -1 && (idxJ == -1 || idxI < idxJ)
+	})
+}
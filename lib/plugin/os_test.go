@@ -0,0 +1,225 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeFile is a file in a directory of the fake operating system.
+type fakeFile struct {
+	name string
+	mode os.FileMode
+}
+
+func (f fakeFile) Name() string       { return f.name }
+func (f fakeFile) Size() int64        { return 0 }
+func (f fakeFile) Mode() os.FileMode  { return f.mode }
+func (f fakeFile) ModTime() time.Time { return time.Time{} }
+func (f fakeFile) IsDir() bool        { return f.mode.IsDir() }
+func (f fakeFile) Sys() interface{}   { return nil }
+
+// fakeOS finds the plugins in directories that only exist in memory.
+type fakeOS struct {
+	goos string
+	env  map[string]string
+	dirs map[string][]fakeFile
+	// links maps a directory to the directory it is a symlink to.
+	links map[string]string
+}
+
+func (f fakeOS) GOOS() string { return f.goos }
+
+func (f fakeOS) Getenv(key string) string { return f.env[key] }
+
+func (f fakeOS) ReadDir(dir string) ([]os.FileInfo, error) {
+	files, ok := f.dirs[dir]
+	if !ok {
+		return nil, fmt.Errorf("the directory %s does not exist", dir)
+	}
+	infos := []os.FileInfo{}
+	for _, file := range files {
+		infos = append(infos, file)
+	}
+	return infos, nil
+}
+
+func (f fakeOS) EvalSymlinks(path string) (string, error) {
+	if target, ok := f.links[path]; ok {
+		return target, nil
+	}
+	if _, ok := f.dirs[path]; !ok {
+		return "", fmt.Errorf("the path %s does not exist", path)
+	}
+	return path, nil
+}
+
+// useOS replaces the operating system used to find the plugins until the end of the test.
+func useOS(t *testing.T, pos pluginOS) {
+	t.Helper()
+	previous := currentOS
+	currentOS = pos
+	t.Cleanup(func() { currentOS = previous })
+}
+
+func TestGetPluginsListFromPathPosix(t *testing.T) {
+	useOS(t, fakeOS{
+		goos: "linux",
+		env:  map[string]string{"PATH": "/usr/bin:/opt/bin::/link/bin:/missing"},
+		dirs: map[string][]fakeFile{
+			"/usr/bin": {
+				{name: "konveyor-foo", mode: 0755},
+				{name: "konveyor-bar_baz", mode: 0755},
+				{name: "konveyor-tackle-analyze", mode: 0755},
+				{name: "konveyor-dir", mode: os.ModeDir | 0755},
+				{name: "kubectl", mode: 0755},
+				{name: "konveyor-noexec", mode: 0644},
+			},
+			"/opt/bin": {
+				{name: "konveyor-foo", mode: 0755},
+				{name: "konveyor-qux.exe", mode: 0755},
+				{name: "Konveyor-upper", mode: 0755},
+			},
+		},
+		links: map[string]string{"/link/bin": "/usr/bin"},
+	})
+	got, err := GetPluginsListFromPath(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	want := []string{
+		filepath.Join("/usr/bin", "konveyor-foo"),
+		filepath.Join("/usr/bin", "konveyor-bar_baz"),
+		filepath.Join("/usr/bin", "konveyor-tackle-analyze"),
+		filepath.Join("/usr/bin", "konveyor-noexec"),
+		filepath.Join("/opt/bin", "konveyor-qux.exe"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the plugins %v, got %v", want, got)
+	}
+	names, err := GetPluginsListFromPath(true)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	wantNames := []string{"konveyor-foo", "konveyor-bar_baz", "konveyor-tackle-analyze", "konveyor-noexec", "konveyor-qux.exe"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("expected the plugin names %v, got %v", wantNames, names)
+	}
+}
+
+func TestGetPluginsListFromPathWindows(t *testing.T) {
+	useOS(t, fakeOS{
+		goos: "windows",
+		env:  map[string]string{"PATH": `C:\bin;"C:\Program Files\konveyor";C:\missing`, "PATHEXT": ".EXE;.BAT"},
+		dirs: map[string][]fakeFile{
+			`C:\bin`: {
+				{name: "Konveyor-Foo.BAT", mode: 0644},
+				{name: "konveyor-foo.exe", mode: 0644},
+				{name: "konveyor-bar.cmd", mode: 0755},
+				{name: "konveyor-baz.txt", mode: 0755},
+				{name: "konveyor-baz", mode: 0755},
+				{name: "KONVEYOR-QUX.bat", mode: 0644},
+				{name: "konveyor-dir.exe", mode: os.ModeDir | 0755},
+			},
+			`C:\Program Files\konveyor`: {
+				{name: "konveyor-foo.exe", mode: 0644},
+				{name: "konveyor-tackle-analyze.EXE", mode: 0644},
+			},
+		},
+	})
+	got, err := GetPluginsListFromPath(false)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	want := []string{
+		filepath.Join(`C:\bin`, "konveyor-foo.exe"),
+		filepath.Join(`C:\bin`, "KONVEYOR-QUX.bat"),
+		filepath.Join(`C:\Program Files\konveyor`, "konveyor-tackle-analyze.EXE"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the plugins %v, got %v", want, got)
+	}
+}
+
+func TestGetExecutableExtensions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		goos    string
+		pathExt string
+		want    []string
+	}{
+		{name: "posix ignores PATHEXT", goos: "linux", pathExt: ".EXE", want: nil},
+		{name: "windows default", goos: "windows", pathExt: "", want: []string{".com", ".exe", ".bat", ".cmd"}},
+		{name: "windows custom", goos: "windows", pathExt: ".PY; exe;;.Exe;.BAT", want: []string{".py", ".exe", ".bat"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := getExecutableExtensions(fakeOS{goos: tc.goos, env: map[string]string{"PATHEXT": tc.pathExt}})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected the extensions %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestGetPluginNameFromFilename(t *testing.T) {
+	testCases := []struct {
+		goos     string
+		filename string
+		want     string
+	}{
+		{goos: "linux", filename: "konveyor-foo", want: "foo"},
+		{goos: "linux", filename: "konveyor-foo-bar_baz", want: "foo bar-baz"},
+		{goos: "linux", filename: "konveyor-foo.exe", want: "foo.exe"},
+		{goos: "linux", filename: "konveyor-foo--bar", want: ""},
+		{goos: "windows", filename: "konveyor-foo.exe", want: "foo"},
+		{goos: "windows", filename: "Konveyor-Foo-Bar_Baz.BAT", want: "foo bar-baz"},
+		{goos: "windows", filename: "konveyor-foo.txt", want: "foo.txt"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.goos+"/"+tc.filename, func(t *testing.T) {
+			useOS(t, fakeOS{goos: tc.goos, env: map[string]string{}})
+			if got := GetPluginNameFromFilename(tc.filename); got != tc.want {
+				t.Fatalf("expected the name '%s', got '%s'", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitPathList(t *testing.T) {
+	testCases := []struct {
+		goos string
+		path string
+		want []string
+	}{
+		{goos: "linux", path: "", want: []string{}},
+		{goos: "linux", path: "/usr/bin:/opt/bin", want: []string{"/usr/bin", "/opt/bin"}},
+		{goos: "windows", path: `C:\bin;"C:\Program Files\konveyor"`, want: []string{`C:\bin`, `C:\Program Files\konveyor`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.goos+"/"+tc.path, func(t *testing.T) {
+			if got := splitPathList(fakeOS{goos: tc.goos}, tc.path); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected the directories %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// detectArmVariant returns the variant of the 32-bit arm architecture. Example: v7
+func detectArmVariant() string {
+	if runtime.GOOS == "linux" {
+		if f, err := os.Open("/proc/cpuinfo"); err == nil {
+			defer f.Close()
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				key, value, found := strings.Cut(scanner.Text(), ":")
+				if !found || strings.TrimSpace(key) != "CPU architecture" {
+					continue
+				}
+				switch v := strings.TrimSpace(value); v {
+				case "5", "6", "7":
+					return "v" + v
+				case "8":
+					// AArch64 CPUs running in 32-bit mode can run v7 code.
+					return "v7"
+				}
+			}
+		}
+	}
+	// Fallback to the variant konveyor was built for.
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOARM" && setting.Value != "" {
+				return "v" + setting.Value
+			}
+		}
+	}
+	return ""
+}
+
+// detectLibc returns the C standard library used on Linux. Returns an empty string if it can't be detected.
+func detectLibc() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	if matches, _ := filepath.Glob("/lib/ld-musl-*.so.1"); len(matches) > 0 {
+		return types.LIBC_MUSL
+	}
+	for _, pattern := range []string{"/lib*/ld-linux*.so.*", "/lib/*/ld-linux*.so.*"} {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return types.LIBC_GLIBC
+		}
+	}
+	return ""
+}
+
+// GetCurrentPlatform returns the platform we are running on.
+func GetCurrentPlatform() types.Platform {
+	platform := types.Platform{Os: runtime.GOOS, Arch: runtime.GOARCH, Libc: detectLibc()}
+	switch runtime.GOARCH {
+	case "arm":
+		platform.Variant = detectArmVariant()
+	case "arm64":
+		platform.Variant = "v8"
+	}
+	return platform
+}
+
+// GetPlatformCandidates returns the platforms we can run plugins built for, from the most to the least preferred.
+func GetPlatformCandidates() []types.PlatformCandidate {
+	current := GetCurrentPlatform()
+	candidates := []types.PlatformCandidate{{Platform: current}}
+	switch {
+	case current.Os == "darwin" && current.Arch == "arm64":
+		amd64 := types.Platform{Os: current.Os, Arch: "amd64"}
+		candidates = append(candidates, types.PlatformCandidate{
+			Platform: amd64,
+			Warning:  "Using the darwin/amd64 build of the plugin. It will run using Rosetta 2 which must be installed.",
+		})
+	case current.Os == "windows" && current.Arch == "arm64":
+		amd64 := types.Platform{Os: current.Os, Arch: "amd64"}
+		candidates = append(candidates, types.PlatformCandidate{
+			Platform: amd64,
+			Warning:  "Using the windows/amd64 build of the plugin. It will run using emulation which requires Windows 11.",
+		})
+	case current.Arch == "arm":
+		// Code built for older arm variants runs on newer ones.
+		for _, variant := range []string{"v7", "v6", "v5"} {
+			if variant < current.Variant {
+				older := current
+				older.Variant = variant
+				candidates = append(candidates, types.PlatformCandidate{Platform: older})
+			}
+		}
+	}
+	return candidates
+}
+
+// getPlatformLabel returns the value of the label for the platform.
+func getPlatformLabel(platform types.Platform, key string) string {
+	switch key {
+	case "os":
+		return platform.Os
+	case "arch":
+		return platform.Arch
+	case "variant":
+		return platform.Variant
+	case "libc":
+		return platform.Libc
+	}
+	return ""
+}
+
+// MatchesPlatform returns true if the selector matches the platform.
+// Empty labels match any platform.
+func MatchesPlatform(selector types.Selector, platform types.Platform) bool {
+	labels := selector.MatchLabels
+	if (labels.Os != "" && labels.Os != platform.Os) ||
+		(labels.Arch != "" && labels.Arch != platform.Arch) ||
+		(labels.Variant != "" && labels.Variant != platform.Variant) ||
+		(labels.Libc != "" && labels.Libc != platform.Libc) {
+		return false
+	}
+	for _, expr := range selector.MatchExpressions {
+		value := getPlatformLabel(platform, expr.Key)
+		switch expr.Operator {
+		case types.SELECTOR_OPERATOR_IN:
+			if !common.Contains(value, expr.Values) {
+				return false
+			}
+		case types.SELECTOR_OPERATOR_NOT_IN:
+			if common.Contains(value, expr.Values) {
+				return false
+			}
+		default:
+			logrus.Warnf("The selector has an invalid operator '%s'. Valid operators are %s and %s", expr.Operator, types.SELECTOR_OPERATOR_IN, types.SELECTOR_OPERATOR_NOT_IN)
+			return false
+		}
+	}
+	return true
+}
+
+// getSelectorSpecificity returns the number of labels and expressions in the selector.
+// A selector that constrains more labels is more specific.
+func getSelectorSpecificity(selector types.Selector) int {
+	specificity := len(selector.MatchExpressions)
+	for _, label := range []string{selector.MatchLabels.Os, selector.MatchLabels.Arch, selector.MatchLabels.Variant, selector.MatchLabels.Libc} {
+		if label != "" {
+			specificity++
+		}
+	}
+	return specificity
+}
+
+// selectPlatform returns the platform of the version that best matches the candidates.
+// The candidates are tried in order and the most specific of the platforms matching a candidate is selected.
+// It returns false if no platform matches and an error if several platforms are equally specific.
+func selectPlatform(version types.PluginVersionMetadata, candidates []types.PlatformCandidate) (types.PluginVersionForPlatform, types.PlatformCandidate, bool, error) {
+	for _, candidate := range candidates {
+		best, bestSpecificity, ties := -1, -1, 0
+		for idx, platform := range version.Platforms {
+			if !MatchesPlatform(platform.Selector, candidate.Platform) {
+				continue
+			}
+			specificity := getSelectorSpecificity(platform.Selector)
+			if specificity > bestSpecificity {
+				best, bestSpecificity, ties = idx, specificity, 0
+			} else if specificity == bestSpecificity {
+				ties++
+			}
+		}
+		if best == -1 {
+			continue
+		}
+		if ties > 0 {
+			return types.PluginVersionForPlatform{}, types.PlatformCandidate{}, false, fmt.Errorf("the version '%s' has %d platforms whose selectors match the platform %s equally well. Make the selectors more specific", version.Version, ties+1, common.GetPlatformAsSingleString(candidate.Platform.Os, candidate.Platform.Arch))
+		}
+		return version.Platforms[best], candidate, true, nil
+	}
+	return types.PluginVersionForPlatform{}, types.PlatformCandidate{}, false, nil
+}
@@ -0,0 +1,76 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+func TestSelectPlatform(t *testing.T) {
+	linux := types.PluginVersionForPlatform{Uri: "linux", Selector: types.Selector{MatchLabels: types.MatchLabels{Os: "linux"}}}
+	linuxAmd64 := types.PluginVersionForPlatform{Uri: "linux-amd64", Selector: types.Selector{MatchLabels: types.MatchLabels{Os: "linux", Arch: "amd64"}}}
+	linuxAmd64Musl := types.PluginVersionForPlatform{Uri: "linux-amd64-musl", Selector: types.Selector{MatchLabels: types.MatchLabels{Os: "linux", Arch: "amd64", Libc: types.LIBC_MUSL}}}
+	linuxNotArm := types.PluginVersionForPlatform{Uri: "linux-not-arm", Selector: types.Selector{
+		MatchLabels:      types.MatchLabels{Os: "linux"},
+		MatchExpressions: []types.MatchExpression{{Key: "arch", Operator: types.SELECTOR_OPERATOR_NOT_IN, Values: []string{"arm", "arm64"}}},
+	}}
+	darwinAmd64 := types.PluginVersionForPlatform{Uri: "darwin-amd64", Selector: types.Selector{MatchLabels: types.MatchLabels{Os: "darwin", Arch: "amd64"}}}
+
+	glibc := []types.PlatformCandidate{{Platform: types.Platform{Os: "linux", Arch: "amd64", Libc: types.LIBC_GLIBC}}}
+	musl := []types.PlatformCandidate{{Platform: types.Platform{Os: "linux", Arch: "amd64", Libc: types.LIBC_MUSL}}}
+	appleSilicon := []types.PlatformCandidate{
+		{Platform: types.Platform{Os: "darwin", Arch: "arm64", Variant: "v8"}},
+		{Platform: types.Platform{Os: "darwin", Arch: "amd64"}, Warning: "rosetta"},
+	}
+
+	testCases := []struct {
+		name        string
+		platforms   []types.PluginVersionForPlatform
+		candidates  []types.PlatformCandidate
+		wantUri     string
+		wantWarning string
+		wantOk      bool
+		wantErr     bool
+	}{
+		{name: "generic before specific", platforms: []types.PluginVersionForPlatform{linux, linuxAmd64Musl, linuxAmd64}, candidates: musl, wantUri: "linux-amd64-musl", wantOk: true},
+		{name: "specific label that does not match", platforms: []types.PluginVersionForPlatform{linuxAmd64Musl, linux, linuxAmd64}, candidates: glibc, wantUri: "linux-amd64", wantOk: true},
+		{name: "only the generic platform", platforms: []types.PluginVersionForPlatform{linux, darwinAmd64}, candidates: glibc, wantUri: "linux", wantOk: true},
+		{name: "expressions count", platforms: []types.PluginVersionForPlatform{linux, linuxNotArm}, candidates: glibc, wantUri: "linux-not-arm", wantOk: true},
+		{name: "fallback candidate", platforms: []types.PluginVersionForPlatform{linux, darwinAmd64}, candidates: appleSilicon, wantUri: "darwin-amd64", wantWarning: "rosetta", wantOk: true},
+		{name: "no match", platforms: []types.PluginVersionForPlatform{darwinAmd64}, candidates: glibc},
+		{name: "tie", platforms: []types.PluginVersionForPlatform{linux, linuxAmd64, linuxNotArm}, candidates: glibc, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			platform, candidate, ok, err := selectPlatform(types.PluginVersionMetadata{Version: "v1.0.0", Platforms: tc.platforms}, tc.candidates)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error: %t, got the error: %v", tc.wantErr, err)
+			}
+			if ok != tc.wantOk {
+				t.Fatalf("expected a platform to be selected: %t, got: %t", tc.wantOk, ok)
+			}
+			if platform.Uri != tc.wantUri {
+				t.Fatalf("expected the platform '%s', got '%s'", tc.wantUri, platform.Uri)
+			}
+			if candidate.Warning != tc.wantWarning {
+				t.Fatalf("expected the warning '%s', got '%s'", tc.wantWarning, candidate.Warning)
+			}
+		})
+	}
+}
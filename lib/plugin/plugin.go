@@ -33,7 +33,43 @@ import (
 
 func isExecutable(mode os.FileMode) bool { return mode&0111 != 0 }
 
-func getKonveyorCommands() []string { return []string{"plugin", "version"} }
+// ValidatePluginName returns an error if the name can't be used for a plugin in the storage directory.
+func ValidatePluginName(name string) error {
+	if !validPluginName.MatchString(name) {
+		return fmt.Errorf("the plugin name '%s' is invalid. It must contain only lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// isInsideDir returns true if the path is strictly inside the directory, after cleaning both.
+func isInsideDir(dir, p string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(p))
+	if err != nil || filepath.IsAbs(rel) {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// removeAllInside removes the path and everything under it.
+// It refuses to remove anything that is not strictly inside the directory.
+func removeAllInside(dir, p string) error {
+	if !isInsideDir(dir, p) {
+		return fmt.Errorf("refusing to remove the path %s since it is not inside the directory %s", p, dir)
+	}
+	return os.RemoveAll(p)
+}
+
+// konveyorCommands are the names of the built-in commands. Plugins and aliases can't use these names.
+var konveyorCommands = []string{}
+
+// SetKonveyorCommands sets the names of the built-in commands.
+func SetKonveyorCommands(names []string) {
+	konveyorCommands = names
+}
+
+func getKonveyorCommands() []string {
+	return konveyorCommands
+}
 
 // getUniquePaths deduplicates the given paths.
 func getUniquePaths(paths []string) []string {
@@ -41,7 +77,7 @@ func getUniquePaths(paths []string) []string {
 	filteredPaths := common.Filter(func(s string) bool { return len(s) > 0 }, trimmedPaths)
 	realPaths := []string{}
 	for _, filteredPath := range filteredPaths {
-		realPath, err := filepath.EvalSymlinks(filteredPath)
+		realPath, err := currentOS.EvalSymlinks(filteredPath)
 		if err != nil {
 			logrus.Debugf("failed to resolve the path %s . Error: %q", filteredPath, err)
 			continue
@@ -73,6 +109,120 @@ func GetPluginsList(nameOnly bool) ([]string, error) {
 	return append(pluginPaths1, pluginPaths2...), nil
 }
 
+// GetPlugins returns all the plugins that can be run, both from the local cache and from the PATH.
+// Installed plugins take precedence over plugins with the same name on the PATH.
+func GetPlugins() ([]types.DiscoveredPlugin, error) {
+	localCache, err := cache.GetLocalCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the local cache. Error: %w", err)
+	}
+	plugins := []types.DiscoveredPlugin{}
+	for i := range localCache.Spec.Installed {
+		installed := localCache.Spec.Installed[i]
+		plugins = append(plugins, types.DiscoveredPlugin{Name: installed.Name, Path: GetPluginBinPath(installed), Installed: &installed})
+	}
+	pluginPaths, err := GetPluginsListFromPath(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the list of plugins from the PATH. Error: %w", err)
+	}
+	konveyorCmds := getKonveyorCommands()
+	for _, pluginPath := range pluginPaths {
+		name := GetPluginNameFromFilename(filepath.Base(pluginPath))
+		if name == "" {
+			logrus.Debugf("The plugin at %s does not have a valid name", pluginPath)
+			continue
+		}
+		if common.Contains(strings.Fields(name)[0], konveyorCmds) {
+			continue
+		}
+		if common.FindIndex(func(p types.DiscoveredPlugin) bool { return p.Name == name }, plugins) != -1 {
+			logrus.Debugf("The plugin '%s' on the PATH at %s is shadowed by an installed plugin", name, pluginPath)
+			continue
+		}
+		plugins = append(plugins, types.DiscoveredPlugin{Name: name, Path: pluginPath})
+	}
+	return plugins, nil
+}
+
+// FindPlugin returns the plugin with the given name, looking in the local cache first and then on the PATH.
+func FindPlugin(name string) (types.DiscoveredPlugin, error) {
+	plugins, err := GetPlugins()
+	if err != nil {
+		return types.DiscoveredPlugin{}, err
+	}
+	idx := common.FindIndex(func(p types.DiscoveredPlugin) bool { return p.Name == name }, plugins)
+	if idx == -1 {
+		return types.DiscoveredPlugin{}, types.ErrPluginNotFound
+	}
+	return plugins[idx], nil
+}
+
+// FindPluginForArgs finds the plugin with the longest name that matches the leading args.
+// Example: for the args "foo bar baz" it tries "foo bar baz", then "foo bar" and finally "foo".
+// It returns the plugin and the remaining args that should be passed to it.
+// Nested plugins on the PATH can also be found using their old dashed names.
+func FindPluginForArgs(args []string) (types.DiscoveredPlugin, []string, error) {
+	words := []string{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		words = append(words, arg)
+	}
+	plugins, err := GetPlugins()
+	if err != nil {
+		return types.DiscoveredPlugin{}, nil, err
+	}
+	for n := len(words); n > 0; n-- {
+		name := strings.Join(words[:n], " ")
+		if idx := common.FindIndex(func(p types.DiscoveredPlugin) bool { return p.Name == name }, plugins); idx != -1 {
+			return plugins[idx], args[n:], nil
+		}
+	}
+	// Plugins on the PATH with dashes in their filename used to be run using the dashed name.
+	// Example: konveyor-tackle-test can still be run as "konveyor tackle-test" instead of "konveyor tackle test".
+	for n := len(words); n > 0; n-- {
+		dashed := strings.Join(words[:n], " ")
+		name := strings.ReplaceAll(dashed, "-", " ")
+		if name == dashed {
+			continue
+		}
+		if idx := common.FindIndex(func(p types.DiscoveredPlugin) bool { return p.Installed == nil && p.Name == name }, plugins); idx != -1 {
+			logrus.Warnf("The plugin at %s is now run as 'konveyor %s'. Running it as 'konveyor %s' is deprecated.", plugins[idx].Path, name, dashed)
+			return plugins[idx], args[n:], nil
+		}
+	}
+	return types.DiscoveredPlugin{}, nil, types.ErrPluginNotFound
+}
+
+// IsPluginGroup returns true if there are nested plugins under the given command name.
+func IsPluginGroup(name string) bool {
+	plugins, err := GetPlugins()
+	if err != nil {
+		return false
+	}
+	return common.FindIndex(func(p types.DiscoveredPlugin) bool { return strings.HasPrefix(p.Name, name+" ") }, plugins) != -1
+}
+
+// GetPluginNameFromFilename returns the name of the command used to run a plugin found on the PATH.
+// Dashes separate nested commands and underscores stand for dashes within a command.
+// Example: konveyor-foo-bar_baz is run as "konveyor foo bar-baz".
+// On Windows the executable extension is removed and the name is lowercase. Example: konveyor-Foo.exe is run as "konveyor foo".
+// Returns an empty string if the filename is not a valid plugin name.
+func GetPluginNameFromFilename(filename string) string {
+	if isWindows(currentOS) {
+		filename, _ = trimExecutableExtension(currentOS, strings.ToLower(filename))
+	}
+	parts := strings.Split(strings.TrimPrefix(filename, types.VALID_PLUGIN_FILENAME_PREFIX), "-")
+	for i, part := range parts {
+		if part == "" {
+			return ""
+		}
+		parts[i] = strings.ReplaceAll(part, "_", "-")
+	}
+	return strings.Join(parts, " ")
+}
+
 // GetPluginsListFromLocalCache gets all the plugins in the storage directory.
 func GetPluginsListFromLocalCache(nameOnly bool) ([]string, error) {
 	localCache, err := cache.GetLocalCache()
@@ -93,46 +243,75 @@ func GetPluginsListFromLocalCache(nameOnly bool) ([]string, error) {
 	return pluginPaths, nil
 }
 
+// GetInstalledPluginDir returns the directory containing the files of the installed version of the plugin.
+// For linked plugins this is the external directory they point at.
+func GetInstalledPluginDir(installed types.InstalledPlugin) string {
+	if installed.Linked {
+		return installed.LinkPath
+	}
+	return filepath.Join(common.GetPluginDir(installed.Name), installed.Version, installed.Platform)
+}
+
 // GetPluginBinPath returns the path to the plugin's entrypoint.
 func GetPluginBinPath(installed types.InstalledPlugin) string {
-	return filepath.Join(common.GetPluginDir(installed.Name), installed.Version, installed.Platform, installed.Bin)
+	return filepath.Join(GetInstalledPluginDir(installed), installed.Bin)
 }
 
 // GetPluginsListFromPath get all the plugins with a valid prefix that are on the PATH.
+// On Windows only the files with an extension listed in PATHEXT are plugins and, for files that
+// differ only in their extension, the one whose extension comes first in PATHEXT is used.
 func GetPluginsListFromPath(nameOnly bool) ([]string, error) {
-	envPath := os.Getenv("PATH")
+	envPath := currentOS.Getenv("PATH")
 	logrus.Debug("envPath", envPath)
-	paths := filepath.SplitList(envPath)
+	paths := splitPathList(currentOS, envPath)
 	logrus.Debug("paths", paths)
 	if len(paths) == 0 {
 		return nil, fmt.Errorf("the list of directories is empty")
 	}
 	pluginPaths := []string{}
 	konveyorCmds := getKonveyorCommands()
-	seen := map[string]bool{}
+	windows := isWindows(currentOS)
+	seen := map[string]string{}
 	for _, dir := range getUniquePaths(paths) {
-		files, err := ioutil.ReadDir(dir)
+		files, err := currentOS.ReadDir(dir)
 		if err != nil {
 			logrus.Errorf("failed to read the directory %s . Error: %q . Skipping...\n", dir, err)
 			continue
 		}
-
+		if windows {
+			sortByExecutableExtension(currentOS, files)
+		}
 		for _, f := range files {
 			if f.IsDir() {
 				continue
 			}
 			pluginName := f.Name()
-			if !strings.HasPrefix(pluginName, types.VALID_PLUGIN_FILENAME_PREFIX) {
+			if !hasPluginPrefix(currentOS, pluginName) {
 				continue
 			}
-			if _, ok := seen[pluginName]; ok {
-				logrus.Warnf("The plugin named '%s' was found in multiple directories. Found again in %s", pluginName, dir)
+			key := pluginName
+			if windows {
+				if !isPluginExecutable(currentOS, f) {
+					logrus.Debugf("The file named '%s' in the directory %s does not have an executable extension. Skipping.", pluginName, dir)
+					continue
+				}
+				// konveyor-foo.exe and konveyor-foo.bat are both run as "konveyor foo".
+				if name := GetPluginNameFromFilename(pluginName); name != "" {
+					key = name
+				}
+			}
+			if seenDir, ok := seen[key]; ok {
+				if seenDir == dir {
+					logrus.Debugf("The file named '%s' in the directory %s is shadowed by a file with an extension that comes earlier in PATHEXT", pluginName, dir)
+				} else {
+					logrus.Warnf("The plugin named '%s' was found in multiple directories. Found again in %s", pluginName, dir)
+				}
 				continue
 			}
-			seen[pluginName] = true
-			if !isExecutable(f.Mode()) {
+			seen[key] = dir
+			if !isPluginExecutable(currentOS, f) {
 				logrus.Warnf("A file named '%s' was found in the directory %s but it is not executable", pluginName, dir)
-			} else if common.Contains(pluginName, konveyorCmds) {
+			} else if name := GetPluginNameFromFilename(pluginName); name != "" && common.Contains(strings.Fields(name)[0], konveyorCmds) {
 				logrus.Warnf("The plugin '%s' has the same name as a built-in command of konveyor", pluginName)
 			}
 			if nameOnly {
@@ -152,25 +331,25 @@ func GetPluginMetadataFromLocalCache(name string) (types.PluginMetadata, error)
 	plugin := types.PluginMetadata{}
 	pluginYaml, err := ioutil.ReadFile(pluginYamlPath)
 	if err != nil {
-		return plugin, fmt.Errorf("failed to get the yaml for the plugin '%s' from the local cache. Error: %w", plugin, err)
+		return plugin, fmt.Errorf("failed to get the yaml for the plugin '%s' from the local cache. Error: %w", name, err)
 	}
 	if err := yaml.Unmarshal(pluginYaml, &plugin); err != nil {
-		return plugin, fmt.Errorf("failed to parse the yaml for the plugin '%s'. Error: %w", plugin, err)
+		return plugin, fmt.Errorf("failed to parse the yaml for the plugin '%s'. Error: %w", name, err)
 	}
 	return plugin, nil
 }
 
-// GetPluginMetadataFromGithub returns the plugin metadata from the Github repo.
+// GetPluginMetadataFromGithub returns the plugin metadata from the Github repo. The archives must be downloaded over http(s).
 func GetPluginMetadataFromGithub(name string) (types.PluginMetadata, error) {
 	plugin := types.PluginMetadata{}
 	pluginYaml, err := github.GetPluginYamlFromGithub(name)
 	if err != nil {
-		return plugin, fmt.Errorf("failed to get the yaml for the plugin '%s' from the Github repo. Error: %w", plugin, err)
+		return plugin, fmt.Errorf("failed to get the yaml for the plugin '%s' from the Github repo. Error: %w", name, err)
 	}
 	if err := yaml.Unmarshal(pluginYaml, &plugin); err != nil {
-		return plugin, fmt.Errorf("failed to parse the yaml for the plugin '%s'. Error: %w", plugin, err)
+		return plugin, fmt.Errorf("failed to parse the yaml for the plugin '%s'. Error: %w", name, err)
 	}
-	return plugin, nil
+	return plugin, checkRemoteUris(plugin, "the Github repo")
 }
 
 func GetPluginFromLocalCache(name string) (types.InstalledPlugin, error) {
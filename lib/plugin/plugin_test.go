@@ -0,0 +1,75 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+func TestFindPluginForArgs(t *testing.T) {
+	useStorageDir(t)
+	useOS(t, fakeOS{
+		goos: "linux",
+		env:  map[string]string{"PATH": "/usr/bin"},
+		dirs: map[string][]fakeFile{
+			"/usr/bin": {
+				{name: "konveyor-tackle-test-generator-cli", mode: 0755},
+				{name: "konveyor-tackle-analyze", mode: 0755},
+				{name: "konveyor-tackle", mode: 0755},
+				{name: "konveyor-foo_bar", mode: 0755},
+			},
+		},
+	})
+	testCases := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantRest []string
+		wantErr  error
+	}{
+		{name: "nested", args: []string{"tackle", "test", "generator", "cli", "--help"}, wantPath: "konveyor-tackle-test-generator-cli", wantRest: []string{"--help"}},
+		{name: "old dashed name", args: []string{"tackle-test-generator-cli", "gen"}, wantPath: "konveyor-tackle-test-generator-cli", wantRest: []string{"gen"}},
+		{name: "nested names come before dashed names", args: []string{"tackle", "test-generator-cli"}, wantPath: "konveyor-tackle", wantRest: []string{"test-generator-cli"}},
+		{name: "longest match", args: []string{"tackle", "analyze", "src"}, wantPath: "konveyor-tackle-analyze", wantRest: []string{"src"}},
+		{name: "group runs the parent", args: []string{"tackle", "other"}, wantPath: "konveyor-tackle", wantRest: []string{"other"}},
+		{name: "old dashed name with args", args: []string{"tackle-analyze", "src"}, wantPath: "konveyor-tackle-analyze", wantRest: []string{"src"}},
+		{name: "escaped dash", args: []string{"foo-bar"}, wantPath: "konveyor-foo_bar", wantRest: []string{}},
+		{name: "not found", args: []string{"bar"}, wantErr: types.ErrPluginNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, rest, err := FindPluginForArgs(tc.args)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected the error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if want := filepath.Join("/usr/bin", tc.wantPath); p.Path != want {
+				t.Fatalf("expected the plugin at %s, got %s", want, p.Path)
+			}
+			if !reflect.DeepEqual(rest, tc.wantRest) {
+				t.Fatalf("expected the remaining args %v, got %v", tc.wantRest, rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+const (
+	// PROCESS_WAIT_DELAY is how long we wait for the output of a process to be closed after it exits or is killed.
+	// Processes it started in the background can keep the output open forever.
+	PROCESS_WAIT_DELAY = 5 * time.Second
+)
+
+// CommandContext is like exec.CommandContext but the process is killed along with the processes it started
+// when the context is done, and waiting for it gives up on the output after PROCESS_WAIT_DELAY.
+// Use this for plugins, hooks and interpreters that konveyor runs with a timeout.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = PROCESS_WAIT_DELAY
+	return cmd
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"os/exec"
+)
+
+// killProcessGroupOnCancel keeps the default of only killing the process on Windows.
+// The processes it started are not killed, the wait delay makes sure we don't wait for them.
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
@@ -0,0 +1,105 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/konveyor/cli/lib"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// checkKonveyorVersion checks that the running version of konveyor satisfies the constraint.
+func checkKonveyorVersion(requires types.PluginRequirements) error {
+	if requires.Konveyor == "" {
+		return nil
+	}
+	ok, err := common.SatisfiesConstraint(lib.GetVersion(), requires.Konveyor)
+	if err != nil {
+		return fmt.Errorf("failed to check the konveyor version requirement. Error: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w : konveyor %s is required but the current version is %s", types.ErrUnmetRequirements, requires.Konveyor, lib.GetVersion())
+	}
+	return nil
+}
+
+// checkBinaries checks that all the required executables are on the PATH.
+func checkBinaries(requires types.PluginRequirements) error {
+	missing := common.Filter(func(bin string) bool {
+		_, err := exec.LookPath(bin)
+		return err != nil
+	}, requires.Binaries)
+	if len(missing) > 0 {
+		return fmt.Errorf("%w : the following executables must be installed and on the PATH: %s", types.ErrUnmetRequirements, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkPluginDependency checks that the dependency is installed with a version that satisfies the constraint.
+func checkPluginDependency(dep types.PluginDependency) error {
+	installed, err := GetPluginFromLocalCache(dep.Name)
+	if err != nil {
+		if errors.Is(err, types.ErrPluginNotInstalled) {
+			return fmt.Errorf("%w : the plugin '%s' must be installed", types.ErrUnmetRequirements, dep.Name)
+		}
+		return err
+	}
+	if dep.Version == "" {
+		return nil
+	}
+	ok, err := common.SatisfiesConstraint(installed.Version, dep.Version)
+	if err != nil {
+		return fmt.Errorf("failed to check the version of the plugin '%s'. Error: %w", dep.Name, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w : the plugin '%s' %s is required but the installed version is %s", types.ErrUnmetRequirements, dep.Name, dep.Version, installed.Version)
+	}
+	return nil
+}
+
+// CheckRequirements checks that all the requirements of the plugin version are satisfied.
+func CheckRequirements(requires types.PluginRequirements) error {
+	if err := checkKonveyorVersion(requires); err != nil {
+		return err
+	}
+	if err := checkBinaries(requires); err != nil {
+		return err
+	}
+	for _, dep := range requires.Plugins {
+		if err := checkPluginDependency(dep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckInstalledPluginRequirements checks that all the requirements of the installed plugin are satisfied.
+// Plugins whose metadata is missing are assumed to have no requirements.
+func CheckInstalledPluginRequirements(installed types.InstalledPlugin) error {
+	version, err := GetPluginVersionMetadata(installed)
+	if err != nil {
+		logrus.Debugf("failed to get the metadata for the installed version of the plugin '%s'. Skipping the requirements check. Error: %q", installed.Name, err)
+		return nil
+	}
+	return CheckRequirements(version.Requires)
+}
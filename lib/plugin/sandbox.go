@@ -0,0 +1,150 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+// sandboxOptions control how much of the user's environment the installed plugins get access to.
+var sandboxOptions = types.SandboxOptions{}
+
+// SetSandboxOptions sets the options used to run the installed plugins.
+func SetSandboxOptions(options types.SandboxOptions) {
+	sandboxOptions = options
+}
+
+// isManagedPlugin returns true for plugins installed from a plugin YAML. Linked plugins and plugins on the PATH are trusted.
+func isManagedPlugin(p types.DiscoveredPlugin) bool {
+	return p.Installed != nil && !p.Installed.Linked
+}
+
+// hasPermissions returns true if the plugin requests any permissions.
+func hasPermissions(permissions types.PluginPermissions) bool {
+	return permissions.Network || len(permissions.Paths) > 0 || len(permissions.Env) > 0
+}
+
+// formatPermissions returns the permissions in a human readable format.
+func formatPermissions(permissions types.PluginPermissions) string {
+	lines := []string{}
+	if permissions.Network {
+		lines = append(lines, "  network: access the network")
+	}
+	for _, path := range permissions.Paths {
+		lines = append(lines, "  path: write to "+path)
+	}
+	for _, env := range permissions.Env {
+		lines = append(lines, "  env: receive the environment variable "+env)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// approvePermissions asks the user to grant the permissions requested by the version of the plugin.
+func approvePermissions(name string, version types.PluginVersionMetadata) error {
+	if !hasPermissions(version.Permissions) {
+		return nil
+	}
+	prompt := fmt.Sprintf("The plugin '%s' %s requests the following permissions:\n%s\nGrant them?", name, version.Version, formatPermissions(version.Permissions))
+	ok, err := confirm(prompt, fmt.Errorf("the plugin '%s' requests permissions but konveyor is not running interactively. Use --yes to grant them", name))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the permissions requested by the plugin '%s' were not granted", name)
+	}
+	return nil
+}
+
+// matchesEnvPattern returns true if the name of the environment variable matches the pattern. A trailing * matches any suffix.
+func matchesEnvPattern(pattern, name string) bool {
+	if runtime.GOOS == "windows" {
+		pattern, name = strings.ToUpper(pattern), strings.ToUpper(name)
+	}
+	if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+		return strings.HasPrefix(name, prefix)
+	}
+	return pattern == name
+}
+
+// getAllowedEnv returns the patterns of the environment variables the version of the plugin is allowed to receive.
+func getAllowedEnv(version types.PluginVersionMetadata) []string {
+	allowed := append([]string{}, types.DEFAULT_PLUGIN_ENV_ALLOWLIST...)
+	if version.Permissions.Network {
+		allowed = append(allowed, types.NETWORK_ENV_ALLOWLIST...)
+	}
+	for key := range version.Env {
+		allowed = append(allowed, key)
+	}
+	return append(allowed, version.Permissions.Env...)
+}
+
+// filterEnvironment removes the environment variables that don't match any of the allowed patterns.
+func filterEnvironment(environment, allowed []string) []string {
+	filtered := []string{}
+	for _, kv := range environment {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, pattern := range allowed {
+			if matchesEnvPattern(pattern, name) {
+				filtered = append(filtered, kv)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// expandPath replaces a leading ~ with the user's home directory.
+func expandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logrus.Debugf("failed to get the home directory. Error: %q", err)
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// GetSandbox returns the restrictions the plugin should be run with.
+// Returns false if the sandbox is disabled or the plugin is not an installed executable.
+// Container image plugins are isolated by the container runtime instead.
+func GetSandbox(p types.DiscoveredPlugin) (types.Sandbox, bool) {
+	if !sandboxOptions.Enabled || !isManagedPlugin(p) || p.Installed.Image != "" {
+		return types.Sandbox{}, false
+	}
+	version, err := GetPluginVersionMetadata(*p.Installed)
+	if err != nil {
+		logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", p.Name, err)
+	}
+	sandbox := types.Sandbox{Network: version.Permissions.Network, WritablePaths: []string{GetPluginDirectory(p), common.GetPluginConfigDir(p.Name), common.GetPluginDataDir(p.Name), os.TempDir()}}
+	if cwd, err := os.Getwd(); err == nil {
+		sandbox.WritablePaths = append(sandbox.WritablePaths, cwd)
+	}
+	for _, path := range version.Permissions.Paths {
+		sandbox.WritablePaths = append(sandbox.WritablePaths, expandPath(path))
+	}
+	return sandbox, true
+}
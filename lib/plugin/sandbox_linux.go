@@ -0,0 +1,114 @@
+//go:build linux
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// landlockWriteAccess are the Landlock access rights that modify the filesystem.
+const landlockWriteAccess = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+// restrictWrites uses Landlock to only allow the current thread and its children to write to the given paths.
+func restrictWrites(paths []string) error {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 || abi < 1 {
+		return fmt.Errorf("%w : Landlock is not supported by the kernel. Error: %v", types.ErrSandboxUnavailable, errno)
+	}
+	attr := unix.LandlockRulesetAttr{Access_fs: landlockWriteAccess}
+	rulesetFd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create the Landlock ruleset. Error: %w", errno)
+	}
+	defer unix.Close(int(rulesetFd))
+	for _, path := range paths {
+		finfo, err := os.Stat(path)
+		if err != nil {
+			logrus.Debugf("The writable path %s does not exist. Skipping. Error: %q", path, err)
+			continue
+		}
+		pathFd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open the path %s . Error: %w", path, err)
+		}
+		rule := unix.LandlockPathBeneathAttr{Allowed_access: landlockWriteAccess, Parent_fd: int32(pathFd)}
+		if !finfo.IsDir() {
+			rule.Allowed_access = unix.LANDLOCK_ACCESS_FS_WRITE_FILE
+		}
+		_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, rulesetFd, unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+		unix.Close(pathFd)
+		if errno != 0 {
+			return fmt.Errorf("failed to allow writes to the path %s . Error: %w", path, errno)
+		}
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs. Error: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, rulesetFd, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce the Landlock ruleset. Error: %w", errno)
+	}
+	return nil
+}
+
+// StartInSandbox starts the command with the restrictions of the sandbox.
+// Writes are restricted using Landlock and the network is isolated using a user and a network namespace.
+func StartInSandbox(cmd *exec.Cmd, sandbox types.Sandbox) error {
+	if !sandbox.Network {
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		cmd.SysProcAttr.Cloneflags |= syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		cmd.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		cmd.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		// Landlock restricts the calling thread. It is never unlocked so that
+		// it exits along with this goroutine instead of being reused by konveyor.
+		runtime.LockOSThread()
+		// /proc has to be writable for the uid and gid mappings of the user namespace to be written.
+		if err := restrictWrites(append(sandbox.WritablePaths, "/dev", "/proc")); err != nil {
+			errCh <- err
+			return
+		}
+		if err := cmd.Start(); err != nil {
+			if !sandbox.Network && (errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOSPC)) {
+				errCh <- fmt.Errorf("%w : failed to create the namespaces to isolate the network. Error: %v", types.ErrSandboxUnavailable, err)
+				return
+			}
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+	return <-errCh
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+// StartInSandbox always fails because the sandbox is only supported on Linux.
+func StartInSandbox(_ *exec.Cmd, _ types.Sandbox) error {
+	return fmt.Errorf("%w : the sandbox is not supported on %s", types.ErrSandboxUnavailable, runtime.GOOS)
+}
@@ -0,0 +1,55 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+func TestFilterEnvironment(t *testing.T) {
+	environment := []string{"PATH=/usr/bin", "KONVEYOR_HOME=/home/me/.konveyor", "SSH_AUTH_SOCK=/tmp/agent.sock", "AWS_REGION=us-east-1", "HTTPS_PROXY=http://proxy:3128", "FOO_MODE=fast"}
+	testCases := []struct {
+		name    string
+		version types.PluginVersionMetadata
+		want    []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"PATH=/usr/bin", "KONVEYOR_HOME=/home/me/.konveyor"},
+		},
+		{
+			name:    "ssh agent requested",
+			version: types.PluginVersionMetadata{Permissions: types.PluginPermissions{Env: []string{"SSH_AUTH_SOCK"}}},
+			want:    []string{"PATH=/usr/bin", "KONVEYOR_HOME=/home/me/.konveyor", "SSH_AUTH_SOCK=/tmp/agent.sock"},
+		},
+		{
+			name:    "network, patterns and declared env",
+			version: types.PluginVersionMetadata{Env: map[string]string{"FOO_MODE": "slow"}, Permissions: types.PluginPermissions{Network: true, Env: []string{"AWS_*"}}},
+			want:    []string{"PATH=/usr/bin", "KONVEYOR_HOME=/home/me/.konveyor", "AWS_REGION=us-east-1", "HTTPS_PROXY=http://proxy:3128", "FOO_MODE=fast"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := filterEnvironment(environment, getAllowedEnv(tc.version)); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected the environment %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/konveyor/cli/lib/github"
+	"github.com/konveyor/cli/lib/types"
+	"gopkg.in/yaml.v3"
+)
+
+// IsPluginManifestSource returns true if the argument to install refers to a plugin YAML instead of a plugin name.
+// Directories must be given as paths. Example: ./my-plugin
+func IsPluginManifestSource(source string) bool {
+	if github.IsURL(source) || strings.HasPrefix(source, "file://") {
+		return true
+	}
+	if ext := filepath.Ext(source); ext == ".yaml" || ext == ".yml" {
+		return true
+	}
+	return strings.ContainsRune(source, '/') || strings.ContainsRune(source, filepath.Separator)
+}
+
+// findManifestInDirectory returns the path to the only plugin YAML in the directory.
+func findManifestInDirectory(dir string) (string, error) {
+	paths := []string{}
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return "", fmt.Errorf("failed to look for plugin YAMLs in the directory %s . Error: %w", dir, err)
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) != 1 {
+		return "", fmt.Errorf("expected the directory %s to contain exactly one plugin YAML. Found %d", dir, len(paths))
+	}
+	return paths[0], nil
+}
+
+// resolveUris makes the relative uris of the plugin archives relative to the location of the plugin YAML.
+func resolveUris(plugin *types.PluginMetadata, resolve func(string) (string, error)) error {
+	for i, version := range plugin.Spec.Versions {
+		for j, platform := range version.Platforms {
+			if platform.Uri == "" {
+				continue
+			}
+			uri, err := resolve(platform.Uri)
+			if err != nil {
+				return fmt.Errorf("failed to resolve the uri '%s' of the version '%s'. Error: %w", platform.Uri, version.Version, err)
+			}
+			plugin.Spec.Versions[i].Platforms[j].Uri = uri
+		}
+	}
+	return nil
+}
+
+// checkRemoteUris checks that a plugin YAML that was not loaded from the local disk only downloads archives over http(s).
+// Otherwise a remote plugin YAML could install files or directories from the user's disk.
+func checkRemoteUris(plugin types.PluginMetadata, source string) error {
+	for _, version := range plugin.Spec.Versions {
+		for _, platform := range version.Platforms {
+			if platform.Uri != "" && !github.IsURL(platform.Uri) {
+				return fmt.Errorf("the uri '%s' of the version '%s' must be an http or https URL since the plugin YAML is from %s", platform.Uri, version.Version, source)
+			}
+		}
+	}
+	return nil
+}
+
+// GetPluginMetadataFromSource returns the plugin metadata from a local file, a directory containing a plugin YAML or a URL.
+// Relative uris in the plugin YAML are resolved relative to the location of the YAML.
+// Only plugin YAMLs on the local disk can refer to local archives and directories.
+func GetPluginMetadataFromSource(source string) (types.PluginMetadata, error) {
+	plugin := types.PluginMetadata{}
+	if !github.IsURL(source) {
+		path := github.GetLocalPath(source)
+		if finfo, err := os.Stat(path); err == nil && finfo.IsDir() {
+			if path, err = findManifestInDirectory(path); err != nil {
+				return plugin, err
+			}
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return plugin, fmt.Errorf("failed to make the path %s absolute. Error: %w", path, err)
+		}
+		source = absPath
+	}
+	pluginYaml, err := github.GetContents(source)
+	if err != nil {
+		return plugin, fmt.Errorf("failed to get the plugin YAML from %s . Error: %w", source, err)
+	}
+	if err := yaml.Unmarshal(pluginYaml, &plugin); err != nil {
+		return plugin, fmt.Errorf("failed to parse the yaml at %s . Error: %w", source, err)
+	}
+	if github.IsURL(source) {
+		baseURL, err := url.Parse(source)
+		if err != nil {
+			return plugin, fmt.Errorf("failed to parse the url %s . Error: %w", source, err)
+		}
+		err = resolveUris(&plugin, func(uri string) (string, error) {
+			ref, err := url.Parse(uri)
+			if err != nil {
+				return "", err
+			}
+			return baseURL.ResolveReference(ref).String(), nil
+		})
+		if err != nil {
+			return plugin, err
+		}
+		return plugin, checkRemoteUris(plugin, source)
+	}
+	err = resolveUris(&plugin, func(uri string) (string, error) {
+		if github.IsURL(uri) || filepath.IsAbs(github.GetLocalPath(uri)) {
+			return uri, nil
+		}
+		return filepath.Join(filepath.Dir(source), uri), nil
+	})
+	return plugin, err
+}
+
+// GetPluginMetadataForArchive returns plugin metadata for a local archive or directory built for the current platform.
+// If bin is empty the executable is assumed to be named after the plugin.
+func GetPluginMetadataForArchive(name, version, archivePath, bin, checkSum string) (types.PluginMetadata, error) {
+	absPath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return types.PluginMetadata{}, fmt.Errorf("failed to make the path %s absolute. Error: %w", archivePath, err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return types.PluginMetadata{}, fmt.Errorf("failed to find the archive at path %s . Error: %w", absPath, err)
+	}
+	if bin == "" {
+		bin = types.VALID_PLUGIN_FILENAME_PREFIX + name
+		if runtime.GOOS == "windows" {
+			bin += ".exe"
+		}
+	}
+	current := GetCurrentPlatform()
+	return types.PluginMetadata{
+		ApiVersion: types.API_VERSION,
+		Kind:       types.PLUGIN_FILE_KIND,
+		Metadata:   types.MetadataInfo{Name: name},
+		Spec: types.PluginMetadataSpec{
+			Versions: []types.PluginVersionMetadata{{
+				Version: version,
+				Platforms: []types.PluginVersionForPlatform{{
+					Selector: types.Selector{MatchLabels: types.MatchLabels{Os: current.Os, Arch: current.Arch}},
+					Uri:      absPath,
+					Sha256:   checkSum,
+					Bin:      bin,
+				}},
+			}},
+		},
+	}, nil
+}
+
+// InstallPluginFromSource installs a plugin using the plugin YAML at the given local path, directory or URL.
+// Returns the name of the plugin.
+func InstallPluginFromSource(source string) (string, error) {
+	plugin, err := GetPluginMetadataFromSource(source)
+	if err != nil {
+		return "", err
+	}
+	name := plugin.Metadata.Name
+	if name == "" {
+		return "", fmt.Errorf("the plugin YAML at %s does not have a name", source)
+	}
+	if err := ValidatePluginName(name); err != nil {
+		return name, err
+	}
+	if _, err := GetPluginFromLocalCache(name); err == nil {
+		return name, types.ErrPluginAlreadyInstalled
+	}
+	return name, InstallPlugin(plugin)
+}
+
+// InstallPluginFromArchive installs a plugin from a local archive or directory built for the current platform.
+func InstallPluginFromArchive(name, version, archivePath, bin, checkSum string) error {
+	if err := ValidatePluginName(name); err != nil {
+		return err
+	}
+	if _, err := GetPluginFromLocalCache(name); err == nil {
+		return types.ErrPluginAlreadyInstalled
+	}
+	plugin, err := GetPluginMetadataForArchive(name, version, archivePath, bin, checkSum)
+	if err != nil {
+		return err
+	}
+	return InstallPlugin(plugin)
+}
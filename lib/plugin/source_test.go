@@ -0,0 +1,88 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSourcePluginYaml = `apiVersion: v1
+kind: Plugin
+metadata:
+  name: foo
+spec:
+  versions:
+    - version: v1.0.0
+      platforms:
+        - selector:
+            matchLabels:
+              os: linux
+          uri: %s
+          bin: konveyor-foo
+`
+
+func TestGetPluginMetadataFromSource(t *testing.T) {
+	// SERVER and DIR in the expected uris are replaced by the server URL and the directory of the local plugin YAML.
+	testCases := []struct {
+		name    string
+		uri     string
+		remote  bool
+		wantUri string
+		wantErr bool
+	}{
+		{name: "remote relative uri", uri: "foo.tar.gz", remote: true, wantUri: "SERVER/plugins/foo.tar.gz"},
+		{name: "remote https uri", uri: "https://example.com/foo.tar.gz", remote: true, wantUri: "https://example.com/foo.tar.gz"},
+		{name: "remote file uri", uri: "file:///etc", remote: true, wantErr: true},
+		{name: "remote windows path", uri: "C:/Users", remote: true, wantErr: true},
+		{name: "local relative uri", uri: "foo.tar.gz", wantUri: "DIR/foo.tar.gz"},
+		{name: "local file uri", uri: "file:///opt/foo", wantUri: "file:///opt/foo"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pluginYaml := fmt.Sprintf(testSourcePluginYaml, tc.uri)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(pluginYaml))
+			}))
+			defer server.Close()
+			dir := t.TempDir()
+			source := server.URL + "/plugins/foo.yaml"
+			if !tc.remote {
+				source = filepath.Join(dir, "foo.yaml")
+				if err := os.WriteFile(source, []byte(pluginYaml), 0644); err != nil {
+					t.Fatalf("failed to write the plugin yaml. Error: %q", err)
+				}
+			}
+			plugin, err := GetPluginMetadataFromSource(source)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected an error: %t, got the error: %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			wantUri := strings.NewReplacer("SERVER", server.URL, "DIR/", dir+string(filepath.Separator)).Replace(tc.wantUri)
+			if got := plugin.Spec.Versions[0].Platforms[0].Uri; got != wantUri {
+				t.Fatalf("expected the uri '%s', got '%s'", wantUri, got)
+			}
+		})
+	}
+}
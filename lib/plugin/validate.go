@@ -0,0 +1,458 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	validPluginName = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	validSha256     = regexp.MustCompile(`^[a-f0-9]{64}$`)
+	validEnvName    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	validConfigKey  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+	typeErrorLine   = regexp.MustCompile(`^line (\d+): (.*)$`)
+	knownOses       = []string{"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js", "linux", "netbsd", "openbsd", "plan9", "solaris", "windows"}
+	knownArches     = []string{"386", "amd64", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle", "ppc64", "ppc64le", "riscv64", "s390x", "wasm"}
+	knownVariants   = []string{"v5", "v6", "v7", "v8"}
+	knownLibcs      = []string{types.LIBC_GLIBC, types.LIBC_MUSL}
+	// scriptExtensions are the extensions of scripts that need an interpreter to run on Windows.
+	scriptExtensions = []string{".sh", ".bash", ".py", ".js", ".mjs", ".rb", ".pl"}
+)
+
+// validator collects the diagnostics for a plugin YAML.
+type validator struct {
+	diagnostics []types.Diagnostic
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, types.Diagnostic{Path: path, Severity: types.SEVERITY_ERROR, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(path, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, types.Diagnostic{Path: path, Severity: types.SEVERITY_WARNING, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidatePluginMetadata checks the plugin metadata for problems.
+func ValidatePluginMetadata(plugin types.PluginMetadata) []types.Diagnostic {
+	v := &validator{}
+	if plugin.ApiVersion != types.API_VERSION {
+		v.errorf("apiVersion", "the apiVersion must be '%s'", types.API_VERSION)
+	}
+	if plugin.Kind != types.PLUGIN_FILE_KIND {
+		v.errorf("kind", "the kind must be '%s'", types.PLUGIN_FILE_KIND)
+	}
+	name := plugin.Metadata.Name
+	if name == "" {
+		v.errorf("metadata.name", "the name is required")
+	} else if !validPluginName.MatchString(name) {
+		v.errorf("metadata.name", "the name '%s' must contain only lowercase letters, digits and dashes", name)
+	} else if common.Contains(name, getKonveyorCommands()) {
+		v.errorf("metadata.name", "the name '%s' is used by a built-in command", name)
+	}
+	spec := plugin.Spec
+	if spec.ShortDescription == "" {
+		v.errorf("spec.shortDescription", "the short description is required")
+	}
+	if spec.Description == "" {
+		v.warnf("spec.description", "the description is missing")
+	}
+	if spec.HomePage == "" {
+		v.warnf("spec.homePage", "the home page is missing")
+	}
+	if spec.Docs == "" {
+		v.warnf("spec.docs", "the documentation link is missing")
+	}
+	links := []struct{ path, uri string }{{"spec.homePage", spec.HomePage}, {"spec.docs", spec.Docs}, {"spec.tutorials", spec.Tutorials}}
+	for _, link := range links {
+		if link.uri != "" && !strings.HasPrefix(link.uri, "https://") {
+			v.warnf(link.path, "the link '%s' should use https", link.uri)
+		}
+	}
+	seenAliases := map[string]bool{}
+	for i, alias := range spec.Aliases {
+		aliasPath := fmt.Sprintf("spec.aliases[%d]", i)
+		if !validAliasName.MatchString(alias) {
+			v.errorf(aliasPath, "the alias '%s' must start with a letter or digit and contain only letters, digits, dots, dashes and underscores", alias)
+		} else if common.Contains(alias, getKonveyorCommands()) {
+			v.errorf(aliasPath, "the alias '%s' is used by a built-in command", alias)
+		} else if alias == name {
+			v.warnf(aliasPath, "the alias '%s' is the same as the name of the plugin", alias)
+		} else if seenAliases[alias] {
+			v.warnf(aliasPath, "the alias '%s' is listed more than once", alias)
+		}
+		seenAliases[alias] = true
+	}
+	seenConfigKeys := map[string]int{}
+	for i, option := range spec.Config {
+		optionPath := fmt.Sprintf("spec.config[%d]", i)
+		v.validateConfigOption(optionPath, option)
+		if j, ok := seenConfigKeys[option.Name]; ok && option.Name != "" {
+			v.errorf(optionPath+".name", "the name '%s' is the same as spec.config[%d]", option.Name, j)
+		} else {
+			seenConfigKeys[option.Name] = i
+		}
+	}
+	if len(spec.Versions) == 0 {
+		v.errorf("spec.versions", "at least one version is required")
+	}
+	seenVersions := map[string]int{}
+	for i, version := range spec.Versions {
+		versionPath := fmt.Sprintf("spec.versions[%d]", i)
+		v.validateVersion(versionPath, version)
+		if j, ok := seenVersions[version.Version]; ok {
+			v.errorf(versionPath+".version", "the version '%s' is the same as spec.versions[%d]", version.Version, j)
+		} else {
+			seenVersions[version.Version] = i
+		}
+		if i > 0 {
+			prev := spec.Versions[i-1].Version
+			if semver.IsValid(prev) && semver.IsValid(version.Version) && semver.Compare(prev, version.Version) < 0 {
+				v.warnf(versionPath+".version", "the versions should be listed from newest to oldest. '%s' is newer than '%s'", version.Version, prev)
+			}
+		}
+	}
+	return v.diagnostics
+}
+
+// validateVersion checks a single version of the plugin.
+func (v *validator) validateVersion(versionPath string, version types.PluginVersionMetadata) {
+	if version.Version == "" {
+		v.errorf(versionPath+".version", "the version is required")
+	} else if !semver.IsValid(version.Version) {
+		v.errorf(versionPath+".version", "the version '%s' is not a valid semantic version. Example: v1.2.3", version.Version)
+	}
+	if constraint := version.Requires.Konveyor; constraint != "" {
+		if err := common.ValidateConstraint(constraint); err != nil {
+			v.errorf(versionPath+".requires.konveyor", "%s", err)
+		}
+	}
+	for i, dep := range version.Requires.Plugins {
+		depPath := fmt.Sprintf("%s.requires.plugins[%d]", versionPath, i)
+		if dep.Name == "" {
+			v.errorf(depPath+".name", "the name of the required plugin is missing")
+		}
+		if dep.Version != "" {
+			if err := common.ValidateConstraint(dep.Version); err != nil {
+				v.errorf(depPath+".version", "%s", err)
+			}
+		}
+	}
+	keys := common.Keys(version.Env)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !validEnvName.MatchString(key) {
+			v.errorf(versionPath+".env."+key, "'%s' is not a valid environment variable name", key)
+		} else if strings.HasPrefix(key, types.ENV_PREFIX) {
+			v.warnf(versionPath+".env."+key, "variables starting with %s are reserved and may be overridden", types.ENV_PREFIX)
+		}
+	}
+	for i, pattern := range version.Permissions.Env {
+		envPath := fmt.Sprintf("%s.permissions.env[%d]", versionPath, i)
+		if pattern == "*" {
+			v.warnf(envPath, "the plugin requests every environment variable including any secrets in them")
+		} else if !validEnvName.MatchString(strings.TrimSuffix(pattern, "*")) {
+			v.errorf(envPath, "'%s' is not a valid environment variable name. A trailing * matches any suffix", pattern)
+		}
+	}
+	for i, path := range version.Permissions.Paths {
+		if !filepath.IsAbs(path) && path != "~" && !strings.HasPrefix(path, "~/") {
+			v.errorf(fmt.Sprintf("%s.permissions.paths[%d]", versionPath, i), "the path '%s' must be absolute or start with ~/", path)
+		}
+	}
+	if len(version.Platforms) == 0 {
+		v.errorf(versionPath+".platforms", "at least one platform is required")
+	}
+	seenSelectors := map[string]int{}
+	for i, platform := range version.Platforms {
+		platformPath := fmt.Sprintf("%s.platforms[%d]", versionPath, i)
+		v.validatePlatform(platformPath, platform)
+		selectorYaml, err := yaml.Marshal(platform.Selector)
+		if err != nil {
+			continue
+		}
+		if j, ok := seenSelectors[string(selectorYaml)]; ok {
+			v.errorf(platformPath+".selector", "the selector is the same as %s.platforms[%d]", versionPath, j)
+			continue
+		}
+		seenSelectors[string(selectorYaml)] = i
+		if i < len(version.Platforms)-1 && platform.Selector.MatchLabels == (types.MatchLabels{}) && len(platform.Selector.MatchExpressions) == 0 {
+			v.warnf(platformPath+".selector", "the selector matches every platform so the platforms after it are never selected")
+		}
+	}
+}
+
+// validatePlatform checks the platform specific metadata of a version.
+func (v *validator) validatePlatform(platformPath string, platform types.PluginVersionForPlatform) {
+	labels := platform.Selector.MatchLabels
+	v.validateLabelValue(platformPath+".selector.matchLabels.os", "os", labels.Os)
+	v.validateLabelValue(platformPath+".selector.matchLabels.arch", "arch", labels.Arch)
+	v.validateLabelValue(platformPath+".selector.matchLabels.variant", "variant", labels.Variant)
+	v.validateLabelValue(platformPath+".selector.matchLabels.libc", "libc", labels.Libc)
+	for i, expr := range platform.Selector.MatchExpressions {
+		exprPath := fmt.Sprintf("%s.selector.matchExpressions[%d]", platformPath, i)
+		if !common.Contains(expr.Key, []string{"os", "arch", "variant", "libc"}) {
+			v.errorf(exprPath+".key", "the key '%s' is invalid. Valid keys are os, arch, variant and libc", expr.Key)
+		} else {
+			for j, value := range expr.Values {
+				v.validateLabelValue(fmt.Sprintf("%s.values[%d]", exprPath, j), expr.Key, value)
+			}
+		}
+		if expr.Operator != types.SELECTOR_OPERATOR_IN && expr.Operator != types.SELECTOR_OPERATOR_NOT_IN {
+			v.errorf(exprPath+".operator", "the operator '%s' is invalid. Valid operators are %s and %s", expr.Operator, types.SELECTOR_OPERATOR_IN, types.SELECTOR_OPERATOR_NOT_IN)
+		}
+		if len(expr.Values) == 0 {
+			v.errorf(exprPath+".values", "at least one value is required")
+		}
+	}
+	if platform.Image != "" {
+		v.validateImagePlatform(platformPath, platform)
+	} else {
+		v.validateArchivePlatform(platformPath, platform)
+		v.validateInterpreter(platformPath, platform)
+	}
+	hooks := []struct {
+		name string
+		hook *types.PluginHook
+	}{{HOOK_POST_INSTALL, platform.PostInstall}, {HOOK_PRE_UNINSTALL, platform.PreUninstall}, {HOOK_CHECK, platform.Check}}
+	for _, h := range hooks {
+		if h.hook == nil {
+			continue
+		}
+		if len(h.hook.Command) == 0 || h.hook.Command[0] == "" {
+			v.errorf(platformPath+"."+h.name+".command", "the command of the %s hook is required", h.name)
+		}
+		if _, err := getHookTimeout(h.name, *h.hook); err != nil {
+			v.errorf(platformPath+"."+h.name+".timeout", "%s", err)
+		}
+	}
+}
+
+// validateArchivePlatform checks the uri, checksum and executable of a platform that downloads an archive.
+func (v *validator) validateArchivePlatform(platformPath string, platform types.PluginVersionForPlatform) {
+	switch {
+	case platform.Uri == "":
+		v.errorf(platformPath+".uri", "the uri is required")
+	case strings.HasPrefix(platform.Uri, "https://"):
+	case strings.HasPrefix(platform.Uri, "http://"):
+		v.errorf(platformPath+".uri", "the uri '%s' must use https", platform.Uri)
+	default:
+		v.warnf(platformPath+".uri", "the uri '%s' is a local path. It can only be used when installing from a local plugin YAML", platform.Uri)
+	}
+	if platform.Sha256 == "" {
+		v.warnf(platformPath+".sha256", "the sha256 checksum is missing so the download can't be verified")
+	} else if !validSha256.MatchString(platform.Sha256) {
+		v.errorf(platformPath+".sha256", "the sha256 checksum must be 64 lowercase hexadecimal characters")
+	}
+	if platform.Bin == "" {
+		v.errorf(platformPath+".bin", "the bin is required")
+	} else if !isSafeRelativePath(platform.Bin) {
+		v.errorf(platformPath+".bin", "the bin '%s' must be a relative path inside the plugin archive", platform.Bin)
+	}
+}
+
+// validateInterpreter checks the interpreter of a platform and warns about scripts that don't declare one.
+func (v *validator) validateInterpreter(platformPath string, platform types.PluginVersionForPlatform) {
+	if platform.Interpreter == "" {
+		if common.Contains(strings.ToLower(path.Ext(platform.Bin)), scriptExtensions) {
+			v.warnf(platformPath+".interpreter", "the bin '%s' is a script. Declare its interpreter so that it also runs on Windows and without the executable bit", platform.Bin)
+		}
+		return
+	}
+	name, constraint := parseInterpreter(platform.Interpreter)
+	if strings.ContainsAny(name, `/\`) {
+		v.errorf(platformPath+".interpreter", "the interpreter '%s' must be the name of an executable on the PATH", name)
+	}
+	if constraint != "" {
+		if err := common.ValidateConstraint(constraint); err != nil {
+			v.errorf(platformPath+".interpreter", "%s", err)
+		}
+	}
+}
+
+// validateConfigOption checks a config key declared in the plugin metadata.
+func (v *validator) validateConfigOption(optionPath string, option types.PluginConfigOption) {
+	if option.Name == "" {
+		v.errorf(optionPath+".name", "the name is required")
+	} else if !validConfigKey.MatchString(option.Name) {
+		v.errorf(optionPath+".name", "the name '%s' must start with a letter and contain only letters, digits, dots, dashes and underscores", option.Name)
+	}
+	if option.Type != "" && !common.Contains(option.Type, types.CONFIG_TYPES) {
+		v.errorf(optionPath+".type", "the type '%s' is not one of %v", option.Type, types.CONFIG_TYPES)
+		return
+	}
+	for j, value := range option.Values {
+		if _, err := parseConfigValue(types.PluginConfigOption{Type: option.Type}, value); err != nil {
+			v.errorf(fmt.Sprintf("%s.values[%d]", optionPath, j), "%s", err)
+		}
+	}
+	if option.Default != "" {
+		if _, err := parseConfigValue(option, option.Default); err != nil {
+			v.errorf(optionPath+".default", "%s", err)
+		}
+	}
+	if option.Description == "" {
+		v.warnf(optionPath+".description", "the description is missing")
+	}
+}
+
+// validateImagePlatform checks a platform that runs a container image.
+func (v *validator) validateImagePlatform(platformPath string, platform types.PluginVersionForPlatform) {
+	if strings.ContainsAny(platform.Image, " \t") {
+		v.errorf(platformPath+".image", "the image '%s' is not a valid image reference", platform.Image)
+	} else if !strings.Contains(platform.Image, "@") {
+		ref := platform.Image[strings.LastIndex(platform.Image, "/")+1:]
+		if !strings.Contains(ref, ":") || strings.HasSuffix(ref, ":latest") {
+			v.warnf(platformPath+".image", "the image '%s' should use a specific tag or digest so that every install gets the same image", platform.Image)
+		}
+	}
+	for _, field := range []struct{ name, value string }{{"uri", platform.Uri}, {"sha256", platform.Sha256}, {"bin", platform.Bin}, {"interpreter", platform.Interpreter}} {
+		if field.value != "" {
+			v.warnf(platformPath+"."+field.name, "the %s is ignored because the platform uses a container image", field.name)
+		}
+	}
+}
+
+// validateLabelValue checks that the value of a platform label is one we know about.
+func (v *validator) validateLabelValue(labelPath, key, value string) {
+	if value == "" {
+		return
+	}
+	switch key {
+	case "os":
+		if !common.Contains(value, knownOses) {
+			v.warnf(labelPath, "the os '%s' is unknown. Example: linux, darwin, windows", value)
+		}
+	case "arch":
+		if !common.Contains(value, knownArches) {
+			v.warnf(labelPath, "the arch '%s' is unknown. Example: amd64, arm64, arm", value)
+		}
+	case "variant":
+		if !common.Contains(value, knownVariants) {
+			v.warnf(labelPath, "the variant '%s' is unknown. Valid variants are %s", value, strings.Join(knownVariants, ", "))
+		}
+	case "libc":
+		if !common.Contains(value, knownLibcs) {
+			v.errorf(labelPath, "the libc '%s' is invalid. Valid values are %s", value, strings.Join(knownLibcs, ", "))
+		}
+	}
+}
+
+// isSafeRelativePath returns true if the path stays inside the directory it is relative to.
+func isSafeRelativePath(p string) bool {
+	p = filepath.ToSlash(p)
+	if path.IsAbs(p) || filepath.IsAbs(p) || filepath.VolumeName(p) != "" {
+		return false
+	}
+	p = path.Clean(p)
+	return p != "." && p != ".." && !strings.HasPrefix(p, "../")
+}
+
+// ValidatePluginYaml checks the plugin YAML for problems.
+// Unknown fields are reported as errors since they are usually typos.
+func ValidatePluginYaml(pluginYaml []byte) []types.Diagnostic {
+	plugin := types.PluginMetadata{}
+	decoder := yaml.NewDecoder(bytes.NewReader(pluginYaml))
+	decoder.KnownFields(true)
+	diagnostics := []types.Diagnostic{}
+	if err := decoder.Decode(&plugin); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			if err == io.EOF {
+				err = fmt.Errorf("the file is empty")
+			}
+			return append(diagnostics, types.Diagnostic{Severity: types.SEVERITY_ERROR, Message: fmt.Sprintf("failed to parse the yaml. Error: %s", err)})
+		}
+		for _, msg := range typeErr.Errors {
+			diagnostic := types.Diagnostic{Severity: types.SEVERITY_ERROR, Message: msg}
+			if matches := typeErrorLine.FindStringSubmatch(msg); matches != nil {
+				diagnostic.Line, _ = strconv.Atoi(matches[1])
+				diagnostic.Message = matches[2]
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	return append(diagnostics, ValidatePluginMetadata(plugin)...)
+}
+
+// ValidatePluginFile checks the plugin YAML at the given path for problems.
+func ValidatePluginFile(pluginYamlPath string) ([]types.Diagnostic, error) {
+	pluginYaml, err := ioutil.ReadFile(pluginYamlPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file at path %s . Error: %w", pluginYamlPath, err)
+	}
+	diagnostics := ValidatePluginYaml(pluginYaml)
+	plugin := types.PluginMetadata{}
+	if err := yaml.Unmarshal(pluginYaml, &plugin); err == nil && plugin.Metadata.Name != "" {
+		expected := plugin.Metadata.Name + filepath.Ext(pluginYamlPath)
+		if filepath.Base(pluginYamlPath) != expected {
+			diagnostics = append(diagnostics, types.Diagnostic{Path: "metadata.name", Severity: types.SEVERITY_WARNING, Message: fmt.Sprintf("the file should be named %s to be found in the plugins repo", expected)})
+		}
+	}
+	for i := range diagnostics {
+		diagnostics[i].File = pluginYamlPath
+	}
+	return diagnostics, nil
+}
+
+// ValidatePluginPath checks the plugin YAML at the given path, or all the plugin YAMLs in the given directory, for problems.
+func ValidatePluginPath(pluginPath string) ([]types.Diagnostic, error) {
+	finfo, err := os.Stat(pluginPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat the path %s . Error: %w", pluginPath, err)
+	}
+	if !finfo.IsDir() {
+		return ValidatePluginFile(pluginPath)
+	}
+	paths := []string{}
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(pluginPath, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for plugin YAMLs in the directory %s . Error: %w", pluginPath, err)
+		}
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("did not find any plugin YAMLs in the directory %s", pluginPath)
+	}
+	sort.Strings(paths)
+	diagnostics := []types.Diagnostic{}
+	for _, pluginYamlPath := range paths {
+		ds, err := ValidatePluginFile(pluginYamlPath)
+		if err != nil {
+			return diagnostics, err
+		}
+		diagnostics = append(diagnostics, ds...)
+	}
+	return diagnostics, nil
+}
@@ -0,0 +1,201 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/konveyor/cli/lib/types"
+)
+
+// validPluginYaml is a plugin YAML without any problems.
+const validPluginYaml = `apiVersion: cli.konveyor.io/v1alpha1
+kind: Plugin
+metadata:
+  name: move2kube
+spec:
+  shortDescription: Move2Kube
+  description: Migrate to Kubernetes
+  homePage: https://move2kube.konveyor.io
+  docs: https://move2kube.konveyor.io/docs
+  aliases: [m2k]
+  versions:
+    - version: v0.3.4
+      requires:
+        konveyor: ">= v0.2.0"
+      platforms:
+        - selector:
+            matchLabels:
+              os: linux
+              arch: amd64
+          uri: https://example.com/move2kube.tar.gz
+          sha256: 0000000000000000000000000000000000000000000000000000000000000000
+          bin: move2kube
+    - version: v0.3.3
+      platforms:
+        - selector:
+            matchLabels:
+              os: linux
+              arch: amd64
+          image: quay.io/konveyor/move2kube:v0.3.3
+`
+
+// getDiagnosticKeys returns the severity and the location of each diagnostic.
+func getDiagnosticKeys(diagnostics []types.Diagnostic) []string {
+	keys := []string{}
+	for _, d := range diagnostics {
+		if d.Path == "" {
+			keys = append(keys, fmt.Sprintf("%s line %d", d.Severity, d.Line))
+			continue
+		}
+		keys = append(keys, d.Severity+" "+d.Path)
+	}
+	return keys
+}
+
+func TestValidatePluginYaml(t *testing.T) {
+	useAliasTestPlugins(t)
+	testCases := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "unknown field",
+			old:  "  homePage:",
+			new:  "  homepage:",
+			want: []string{"error line 8", "warning spec.homePage"},
+		},
+		{
+			name: "built-in command name",
+			old:  "name: move2kube",
+			new:  "name: plugin",
+			want: []string{"error metadata.name"},
+		},
+		{
+			name: "invalid alias",
+			old:  "aliases: [m2k]",
+			new:  "aliases: [m2k, -x, m2k, help]",
+			want: []string{"error spec.aliases[1]", "warning spec.aliases[2]", "error spec.aliases[3]"},
+		},
+		{
+			name: "invalid constraint",
+			old:  ">= v0.2.0",
+			new:  ">= latest",
+			want: []string{"error spec.versions[0].requires.konveyor"},
+		},
+		{
+			name: "versions out of order",
+			old:  "version: v0.3.3",
+			new:  "version: v0.4.0",
+			want: []string{"warning spec.versions[1].version"},
+		},
+		{
+			name: "duplicate version",
+			old:  "version: v0.3.3",
+			new:  "version: v0.3.4",
+			want: []string{"error spec.versions[1].version"},
+		},
+		{
+			name: "http uri",
+			old:  "uri: https://",
+			new:  "uri: http://",
+			want: []string{"error spec.versions[0].platforms[0].uri"},
+		},
+		{
+			name: "invalid checksum and bin",
+			old:  "sha256: 0000000000000000000000000000000000000000000000000000000000000000\n          bin: move2kube",
+			new:  "sha256: abc\n          bin: ../move2kube",
+			want: []string{"error spec.versions[0].platforms[0].sha256", "error spec.versions[0].platforms[0].bin"},
+		},
+		{
+			name: "image without a tag",
+			old:  "move2kube:v0.3.3",
+			new:  "move2kube",
+			want: []string{"warning spec.versions[1].platforms[0].image"},
+		},
+		{
+			name: "unknown os and invalid libc",
+			old:  "              os: linux\n              arch: amd64\n          image",
+			new:  "              os: macos\n              arch: amd64\n              libc: uclibc\n          image",
+			want: []string{"warning spec.versions[1].platforms[0].selector.matchLabels.os", "error spec.versions[1].platforms[0].selector.matchLabels.libc"},
+		},
+		{
+			name: "not yaml",
+			old:  "kind: Plugin",
+			new:  "kind: [Plugin",
+			want: []string{"error line 0"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pluginYaml := validPluginYaml
+			if tc.old != "" {
+				if !strings.Contains(pluginYaml, tc.old) {
+					t.Fatalf("the plugin yaml doesn't contain '%s'", tc.old)
+				}
+				pluginYaml = strings.Replace(pluginYaml, tc.old, tc.new, 1)
+			}
+			got := getDiagnosticKeys(ValidatePluginYaml([]byte(pluginYaml)))
+			want := tc.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected the diagnostics %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestValidatePluginPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"move2kube.yaml": validPluginYaml,
+		"m2k.yml":        validPluginYaml,
+		"README.md":      "not a plugin",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write the file %s . Error: %q", name, err)
+		}
+	}
+	diagnostics, err := ValidatePluginPath(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %q", err)
+	}
+	// Only the file that isn't named after the plugin has a problem.
+	if len(diagnostics) != 1 || diagnostics[0].File != filepath.Join(dir, "m2k.yml") || diagnostics[0].Path != "metadata.name" {
+		t.Fatalf("expected a single diagnostic about the name of m2k.yml, got %v", diagnostics)
+	}
+	if _, err := ValidatePluginPath(filepath.Join(dir, "missing")); err == nil {
+		t.Fatalf("expected an error for a path that doesn't exist")
+	}
+	if _, err := ValidatePluginPath(t.TempDir()); err == nil {
+		t.Fatalf("expected an error for a directory without plugin YAMLs")
+	}
+}
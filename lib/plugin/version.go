@@ -0,0 +1,87 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PLUGIN_VERSION_TIMEOUT is the maximum time a plugin is given to print its own version.
+	PLUGIN_VERSION_TIMEOUT = 5 * time.Second
+)
+
+// getPluginReportedVersion runs the plugin with the arguments that make it print its own version.
+func getPluginReportedVersion(p types.DiscoveredPlugin, versionArgs []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), PLUGIN_VERSION_TIMEOUT)
+	defer cancel()
+	environment := GetPluginEnvironment(p, "")
+	executable, args, err := GetPluginCommand(p, versionArgs, environment, false)
+	if err != nil {
+		return "", err
+	}
+	versionCmd := CommandContext(ctx, executable, args...)
+	versionCmd.Env = environment
+	output, err := versionCmd.Output()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("the plugin did not print its version within %s", PLUGIN_VERSION_TIMEOUT)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run the plugin with the args %v . Error: %w", versionArgs, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetPluginVersionInfos returns the versions of all the plugins that can be run.
+// Plugins that declare versionArgs are also asked for their own version.
+func GetPluginVersionInfos() ([]types.PluginVersionInfo, error) {
+	plugins, err := GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	infos := []types.PluginVersionInfo{}
+	for _, p := range plugins {
+		info := types.PluginVersionInfo{Name: p.Name, Source: types.PLUGIN_SOURCE_PATH, Path: p.Path}
+		if p.Installed == nil {
+			infos = append(infos, info)
+			continue
+		}
+		info.Version = p.Installed.Version
+		info.Platform = p.Installed.Platform
+		info.Source = types.PLUGIN_SOURCE_INSTALLED
+		if p.Installed.Linked {
+			info.Source = types.PLUGIN_SOURCE_LINKED
+		}
+		if version, err := GetPluginVersionMetadata(*p.Installed); err != nil {
+			logrus.Debugf("failed to get the metadata for the plugin '%s'. Error: %q", p.Name, err)
+		} else if len(version.VersionArgs) > 0 {
+			if info.ReportedVersion, err = getPluginReportedVersion(p, version.VersionArgs); err != nil {
+				info.Error = err.Error()
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,131 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package stats
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/types"
+	"gopkg.in/yaml.v3"
+)
+
+// enabled is true if the usage of the plugins should be recorded.
+var enabled = true
+
+// SetEnabled turns the recording of the usage of the plugins on or off.
+func SetEnabled(e bool) {
+	enabled = e
+}
+
+// IsEnabled returns true if the usage of the plugins is recorded.
+func IsEnabled() bool {
+	return enabled
+}
+
+// GetStatsPath returns the path to the stats file.
+func GetStatsPath() string {
+	return filepath.Join(common.GetStorageDir(), types.STATS_FILE)
+}
+
+// GetStats returns the usage statistics of the plugins.
+func GetStats() (types.StatsFile, error) {
+	stats := types.StatsFile{
+		ApiVersion: types.API_VERSION,
+		Kind:       types.STATS_FILE_KIND,
+		Metadata:   types.MetadataInfo{Name: "stats"},
+	}
+	statsPath := GetStatsPath()
+	statsYaml, err := ioutil.ReadFile(statsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, fmt.Errorf("failed to read the stats file at path %s . Error: %w", statsPath, err)
+	}
+	if err := yaml.Unmarshal(statsYaml, &stats); err != nil {
+		return stats, fmt.Errorf("failed to unmarshal the stats from yaml. Error: %w", err)
+	}
+	return stats, nil
+}
+
+// GetPluginStats returns the usage statistics of each plugin, keyed by the name of the plugin.
+func GetPluginStats() (map[string]types.PluginStats, error) {
+	stats, err := GetStats()
+	if err != nil {
+		return nil, err
+	}
+	pluginStats := map[string]types.PluginStats{}
+	for _, s := range stats.Spec.Plugins {
+		pluginStats[s.Name] = s
+	}
+	return pluginStats, nil
+}
+
+// SaveStats saves the usage statistics of the plugins to file.
+// The file is replaced in one step so that it is never left half written.
+func SaveStats(stats types.StatsFile) error {
+	storageDir := common.GetStorageDir()
+	if err := os.MkdirAll(storageDir, types.DEFAULT_DIRECTORY_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to create the storage directory %s . Error: %w", storageDir, err)
+	}
+	sort.Slice(stats.Spec.Plugins, func(i, j int) bool { return stats.Spec.Plugins[i].Name < stats.Spec.Plugins[j].Name })
+	statsYaml, err := yaml.Marshal(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the stats to yaml. Error: %w", err)
+	}
+	statsPath := GetStatsPath()
+	if err := common.WriteFileAtomic(statsPath, statsYaml, types.DEFAULT_FILE_PERMISSIONS); err != nil {
+		return fmt.Errorf("failed to write the stats to a file at path %s . Error: %w", statsPath, err)
+	}
+	return nil
+}
+
+// Record counts a run of the plugin that started at the given time.
+// The duration is only added to the average if timed is true.
+// The stats file is locked so that plugins finishing at the same time don't lose each other's runs.
+func Record(name string, start time.Time, duration time.Duration, timed bool) error {
+	unlock, err := common.LockFile(GetStatsPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	stats, err := GetStats()
+	if err != nil {
+		return err
+	}
+	idx := common.FindIndex(func(s types.PluginStats) bool { return s.Name == name }, stats.Spec.Plugins)
+	if idx == -1 {
+		stats.Spec.Plugins = append(stats.Spec.Plugins, types.PluginStats{Name: name})
+		idx = len(stats.Spec.Plugins) - 1
+	}
+	s := &stats.Spec.Plugins[idx]
+	s.Runs++
+	if start.After(s.LastUsed) {
+		s.LastUsed = start.UTC()
+	}
+	if timed {
+		s.TimedRuns++
+		s.TotalDurationMs += duration.Milliseconds()
+	}
+	return SaveStats(stats)
+}
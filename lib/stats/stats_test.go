@@ -0,0 +1,109 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/konveyor/cli/lib/common"
+)
+
+// useStorageDir points the storage directory at a temporary directory until the end of the test.
+func useStorageDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	common.SetStorageDir(dir)
+	t.Cleanup(func() { common.SetStorageDir("") })
+	return dir
+}
+
+func TestRecord(t *testing.T) {
+	useStorageDir(t)
+	start := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		start    time.Time
+		duration time.Duration
+		timed    bool
+	}{
+		{name: "foo", start: start, duration: 2 * time.Second, timed: true},
+		{name: "foo", start: start.Add(-time.Hour), timed: false},
+		{name: "foo", start: start.Add(time.Hour), duration: 4 * time.Second, timed: true},
+		{name: "bar", start: start, duration: time.Second, timed: true},
+	}
+	for _, tc := range testCases {
+		if err := Record(tc.name, tc.start, tc.duration, tc.timed); err != nil {
+			t.Fatalf("failed to record the run of the plugin '%s'. Error: %q", tc.name, err)
+		}
+	}
+	pluginStats, err := GetPluginStats()
+	if err != nil {
+		t.Fatalf("failed to get the stats. Error: %q", err)
+	}
+	foo := pluginStats["foo"]
+	if foo.Runs != 3 || foo.TimedRuns != 2 {
+		t.Fatalf("expected 3 runs of which 2 are timed, got %d runs of which %d are timed", foo.Runs, foo.TimedRuns)
+	}
+	if !foo.LastUsed.Equal(start.Add(time.Hour)) {
+		t.Fatalf("expected the plugin to be last used at %s, got %s", start.Add(time.Hour), foo.LastUsed)
+	}
+	if avg := foo.AverageDuration(); avg != 3*time.Second {
+		t.Fatalf("expected an average duration of 3s, got %s", avg)
+	}
+	if bar := pluginStats["bar"]; bar.Runs != 1 {
+		t.Fatalf("expected the plugin 'bar' to have 1 run, got %d", bar.Runs)
+	}
+}
+
+func TestRecordConcurrently(t *testing.T) {
+	storageDir := useStorageDir(t)
+	const runs = 20
+	wg := sync.WaitGroup{}
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Record("foo", time.Now(), time.Second, true); err != nil {
+				t.Errorf("failed to record the run. Error: %q", err)
+			}
+		}()
+	}
+	wg.Wait()
+	pluginStats, err := GetPluginStats()
+	if err != nil {
+		t.Fatalf("failed to get the stats. Error: %q", err)
+	}
+	if got := pluginStats["foo"].Runs; got != runs {
+		t.Fatalf("expected %d runs, got %d", runs, got)
+	}
+	// Only the stats file and its lock file are left behind.
+	matches, err := filepath.Glob(filepath.Join(storageDir, "*stats*"))
+	if err != nil {
+		t.Fatalf("failed to list the storage directory. Error: %q", err)
+	}
+	want := []string{GetStatsPath(), GetStatsPath() + common.LOCK_FILE_SUFFIX}
+	if len(matches) != len(want) || matches[0] != want[0] || matches[1] != want[1] {
+		t.Fatalf("expected the files %v, got %v", want, matches)
+	}
+	if _, err := os.Stat(GetStatsPath()); err != nil {
+		t.Fatalf("expected the stats file to exist. Error: %q", err)
+	}
+}
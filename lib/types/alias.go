@@ -0,0 +1,45 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+// AliasesFile contains the aliases defined by the user.
+type AliasesFile struct {
+	ApiVersion string       `yaml:"apiVersion"`
+	Kind       string       `yaml:"kind"`
+	Metadata   MetadataInfo `yaml:"metadata"`
+	Spec       AliasesSpec  `yaml:"spec"`
+}
+
+// AliasesSpec contains the aliases defined by the user.
+type AliasesSpec struct {
+	Aliases []UserAlias `yaml:"aliases"`
+}
+
+// UserAlias is a shortcut defined by the user for a command and its leading args.
+type UserAlias struct {
+	Name string `yaml:"name"`
+	// Command is the command and the args that the alias expands to. Example: [move2kube, transform, --qa-skip]
+	Command []string `yaml:"command"`
+}
+
+// Alias is a shortcut for a command, either defined by the user or declared by an installed plugin.
+type Alias struct {
+	Name    string   `yaml:"name" json:"name"`
+	Command []string `yaml:"command" json:"command"`
+	// Source is either user or plugin.
+	Source string `yaml:"source" json:"source"`
+}
@@ -16,6 +16,8 @@
 
 package types
 
+import "time"
+
 // LocalCache contains the list of installed plugins and other app specific metadata.
 type LocalCache struct {
 	ApiVersion string         `yaml:"apiVersion"`
@@ -35,4 +37,14 @@ type InstalledPlugin struct {
 	Version  string `yaml:"version"`
 	Platform string `yaml:"platform"`
 	Bin      string `yaml:"bin"`
+	// Linked is true for development plugins that point at an external directory instead of being installed.
+	Linked bool `yaml:"linked,omitempty"`
+	// LinkPath is the directory containing the executable of a linked plugin.
+	LinkPath string `yaml:"linkPath,omitempty"`
+	// Image is the container image that is run for container image plugins.
+	Image string `yaml:"image,omitempty"`
+	// Interpreter runs the bin of script plugins. Example: python3 >=3.8
+	Interpreter string `yaml:"interpreter,omitempty"`
+	// InstalledAt is when the plugin was installed or linked.
+	InstalledAt time.Time `yaml:"installedAt,omitempty"`
 }
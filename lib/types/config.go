@@ -0,0 +1,68 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+// Config contains the user's configuration.
+type Config struct {
+	ApiVersion string       `yaml:"apiVersion"`
+	Kind       string       `yaml:"kind"`
+	Metadata   MetadataInfo `yaml:"metadata"`
+	Spec       ConfigSpec   `yaml:"spec"`
+}
+
+// ConfigSpec contains the default values for the app settings and the command flags.
+type ConfigSpec struct {
+	LogLevel   string           `yaml:"logLevel,omitempty"`
+	Output     string           `yaml:"output,omitempty"`
+	StorageDir string           `yaml:"storageDir,omitempty"`
+	PluginRepo PluginRepoConfig `yaml:"pluginRepo,omitempty"`
+	// ContainerRuntime is the name or path of the executable used to run container image plugins. Example: podman
+	ContainerRuntime string        `yaml:"containerRuntime,omitempty"`
+	Sandbox          SandboxConfig `yaml:"sandbox,omitempty"`
+	History          HistoryConfig `yaml:"history,omitempty"`
+	Stats            StatsConfig   `yaml:"stats,omitempty"`
+	// Flags contains the default values for the flags of the commands.
+	// The keys are of the form <command>.<subcommand>.<flag> Example: plugin.list.name-only
+	Flags map[string]string `yaml:"flags,omitempty"`
+}
+
+// PluginRepoConfig contains the Github repo where the metadata for the plugins are stored.
+type PluginRepoConfig struct {
+	Owner  string `yaml:"owner,omitempty"`
+	Name   string `yaml:"name,omitempty"`
+	Branch string `yaml:"branch,omitempty"`
+}
+
+// SandboxConfig contains the restrictions the installed plugins are run with.
+type SandboxConfig struct {
+	// Env is either allowlist (the default) to only pass the allowed environment variables to plugins or all.
+	Env string `yaml:"env,omitempty"`
+	// Enabled is true to run the installed plugins in a sandbox when the platform supports it.
+	Enabled string `yaml:"enabled,omitempty"`
+}
+
+// HistoryConfig controls the recording of the runs of the plugins.
+type HistoryConfig struct {
+	// Enabled is false to stop recording the runs of the plugins. Defaults to true.
+	Enabled string `yaml:"enabled,omitempty"`
+}
+
+// StatsConfig controls the recording of the usage statistics of the plugins.
+type StatsConfig struct {
+	// Enabled is false to stop recording the usage of the plugins. Defaults to true.
+	Enabled string `yaml:"enabled,omitempty"`
+}
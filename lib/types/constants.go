@@ -19,6 +19,8 @@ package types
 import "os"
 
 const (
+	// CHECKSUM_FILE_SUFFIX is the suffix of the files containing the sha256 checksums of release archives.
+	CHECKSUM_FILE_SUFFIX = ".sha256sum"
 	// VALID_PLUGIN_FILENAME_PREFIX is the prefix used for plugin filenames.
 	VALID_PLUGIN_FILENAME_PREFIX = "konveyor-"
 	// DEFAULT_FILE_PERMISSIONS is the default permissions to use when creaing a new file.
@@ -29,10 +31,165 @@ const (
 	STORAGE_DIR = ".konveyor"
 	// PLUGINS_DIR is where all the plugins are stored.
 	PLUGINS_DIR = "plugins"
+	// PLUGIN_CONFIG_DIR contains the configuration of each plugin. It is kept when the plugin is upgraded or uninstalled.
+	PLUGIN_CONFIG_DIR = "plugin-config"
+	// PLUGIN_DATA_DIR contains the data of each plugin. It is kept when the plugin is upgraded or uninstalled.
+	PLUGIN_DATA_DIR = "plugin-data"
 	// CACHE_FILE contains the list of installed plugins and other app specific metadata.
 	CACHE_FILE = "cache.yaml"
+	// CONFIG_FILE contains the user's configuration.
+	CONFIG_FILE = "config.yaml"
+	// ALIASES_FILE contains the aliases defined by the user.
+	ALIASES_FILE = "aliases.yaml"
+	// STATS_FILE contains the usage statistics of the plugins.
+	STATS_FILE = "stats.yaml"
+	// PLUGIN_DESCRIPTION_FILE contains the description of the commands of a plugin, stored in the plugin's directory.
+	PLUGIN_DESCRIPTION_FILE = "description.json"
+	// PLUGIN_DESCRIBE_ARG makes plugins that support the introspection protocol print the description of their commands.
+	PLUGIN_DESCRIBE_ARG = "__konveyor_describe"
 	// API_VERSION is the apiVersion (similar to K8s) used by our app specific files.
 	API_VERSION = "cli.konveyor.io/v1alpha1"
 	// KIND is the kind (similar to K8s) used by our app's local cache.
 	CACHE_FILE_KIND = "Cache"
+	// PLUGIN_FILE_KIND is the kind (similar to K8s) used by the plugin metadata files.
+	PLUGIN_FILE_KIND = "Plugin"
+	// CONFIG_FILE_KIND is the kind (similar to K8s) used by the user's configuration file.
+	CONFIG_FILE_KIND = "Config"
+	// ALIASES_FILE_KIND is the kind (similar to K8s) used by the user's aliases file.
+	ALIASES_FILE_KIND = "Aliases"
+	// STATS_FILE_KIND is the kind (similar to K8s) used by the usage statistics file.
+	STATS_FILE_KIND = "Stats"
+	// PLUGIN_DESCRIPTION_KIND is the kind (similar to K8s) printed by plugins that describe their commands.
+	PLUGIN_DESCRIPTION_KIND = "PluginDescription"
+)
+
+const (
+	// ENV_PREFIX is the prefix of all the environment variables used by the app.
+	ENV_PREFIX = "KONVEYOR_"
+	// ENV_CONFIG can be used to point to a different configuration file.
+	ENV_CONFIG = ENV_PREFIX + "CONFIG"
+	// ENV_HOME overrides the storage directory.
+	ENV_HOME = ENV_PREFIX + "HOME"
+	// ENV_LOG_LEVEL overrides the log level.
+	ENV_LOG_LEVEL = ENV_PREFIX + "LOG_LEVEL"
+	// ENV_OUTPUT overrides the output format.
+	ENV_OUTPUT = ENV_PREFIX + "OUTPUT"
+	// ENV_BIN is the path to the konveyor executable that ran the plugin.
+	ENV_BIN = ENV_PREFIX + "BIN"
+	// ENV_VERSION is the version of konveyor that ran the plugin.
+	ENV_VERSION = ENV_PREFIX + "VERSION"
+	// ENV_PLUGIN_NAME is the name of the plugin being run.
+	ENV_PLUGIN_NAME = ENV_PREFIX + "PLUGIN_NAME"
+	// ENV_PLUGIN_VERSION is the installed version of the plugin being run. Empty for plugins on the PATH.
+	ENV_PLUGIN_VERSION = ENV_PREFIX + "PLUGIN_VERSION"
+	// ENV_PLUGIN_DIR is the directory containing the plugin being run.
+	ENV_PLUGIN_DIR = ENV_PREFIX + "PLUGIN_DIR"
+	// ENV_PLUGIN_CONFIG_DIR is the directory where the installed plugin being run stores its configuration.
+	ENV_PLUGIN_CONFIG_DIR = ENV_PREFIX + "PLUGIN_CONFIG_DIR"
+	// ENV_PLUGIN_DATA_DIR is the directory where the installed plugin being run stores its data.
+	ENV_PLUGIN_DATA_DIR = ENV_PREFIX + "PLUGIN_DATA_DIR"
+	// ENV_CONTAINER_RUNTIME overrides the executable used to run container image plugins.
+	ENV_CONTAINER_RUNTIME = ENV_PREFIX + "CONTAINER_RUNTIME"
+)
+
+const (
+	// OUTPUT_FORMAT_YAML is used to print the output of a command as YAML.
+	OUTPUT_FORMAT_YAML = "yaml"
+	// OUTPUT_FORMAT_JSON is used to print the output of a command as JSON.
+	OUTPUT_FORMAT_JSON = "json"
+)
+
+// OUTPUT_FORMATS contains the supported output formats.
+var OUTPUT_FORMATS = []string{OUTPUT_FORMAT_YAML, OUTPUT_FORMAT_JSON}
+
+const (
+	// SELECTOR_OPERATOR_IN matches if the label value is one of the values.
+	SELECTOR_OPERATOR_IN = "In"
+	// SELECTOR_OPERATOR_NOT_IN matches if the label value is none of the values.
+	SELECTOR_OPERATOR_NOT_IN = "NotIn"
+	// LIBC_GLIBC is the GNU C library used by most Linux distributions.
+	LIBC_GLIBC = "glibc"
+	// LIBC_MUSL is the musl C library used by Alpine Linux.
+	LIBC_MUSL = "musl"
+)
+
+const (
+	// SEVERITY_ERROR is used for problems that make a plugin YAML unusable.
+	SEVERITY_ERROR = "error"
+	// SEVERITY_WARNING is used for problems that should be fixed but don't prevent the plugin from being installed.
+	SEVERITY_WARNING = "warning"
+)
+
+const (
+	// SANDBOX_ENV_ALLOWLIST only passes the allowed environment variables to the installed plugins.
+	SANDBOX_ENV_ALLOWLIST = "allowlist"
+	// SANDBOX_ENV_ALL passes the user's whole environment to the installed plugins.
+	SANDBOX_ENV_ALL = "all"
+)
+
+// SANDBOX_ENV_MODES contains the valid values for the sandbox.env setting.
+var SANDBOX_ENV_MODES = []string{SANDBOX_ENV_ALLOWLIST, SANDBOX_ENV_ALL}
+
+// DEFAULT_PLUGIN_ENV_ALLOWLIST are the environment variables that every installed plugin receives.
+// A trailing * matches any suffix. Other variables have to be requested in the permissions of the plugin.
+// Credentials like SSH_AUTH_SOCK are never in this list.
+var DEFAULT_PLUGIN_ENV_ALLOWLIST = []string{
+	ENV_PREFIX + "*", "PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "COLORTERM", "LANG", "LANGUAGE", "LC_*", "TZ",
+	"TMPDIR", "TMP", "TEMP", "XDG_*", "DISPLAY", "NO_COLOR",
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "USERPROFILE", "HOMEDRIVE", "HOMEPATH",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES", "PROGRAMFILES(X86)", "NUMBER_OF_PROCESSORS", "PROCESSOR_ARCHITECTURE",
+}
+
+// NETWORK_ENV_ALLOWLIST are the environment variables that plugins requesting network access also receive.
+var NETWORK_ENV_ALLOWLIST = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy", "SSL_CERT_FILE", "SSL_CERT_DIR"}
+
+// CONTAINER_RUNTIMES are the executables used to run container image plugins, in order of preference.
+var CONTAINER_RUNTIMES = []string{"docker", "podman"}
+
+const (
+	// CONTAINER_WORKDIR is the directory inside the container where the current working directory is mounted.
+	CONTAINER_WORKDIR = "/workspace"
+)
+
+const (
+	// PLUGIN_SOURCE_INSTALLED is used for plugins installed in the storage directory.
+	PLUGIN_SOURCE_INSTALLED = "installed"
+	// PLUGIN_SOURCE_LINKED is used for plugins linked to an external directory.
+	PLUGIN_SOURCE_LINKED = "linked"
+	// PLUGIN_SOURCE_PATH is used for plugins found on the PATH.
+	PLUGIN_SOURCE_PATH = "path"
+)
+
+const (
+	// PLUGIN_SORT_NAME sorts the list of plugins by name.
+	PLUGIN_SORT_NAME = "name"
+	// PLUGIN_SORT_LAST_USED sorts the list of plugins by the time they were last run, most recent first.
+	PLUGIN_SORT_LAST_USED = "last-used"
+)
+
+// PLUGIN_SORT_ORDERS contains the orders the list of plugins can be sorted in.
+var PLUGIN_SORT_ORDERS = []string{PLUGIN_SORT_NAME, PLUGIN_SORT_LAST_USED}
+
+const (
+	// ALIAS_SOURCE_USER is used for aliases defined by the user.
+	ALIAS_SOURCE_USER = "user"
+	// ALIAS_SOURCE_PLUGIN is used for aliases declared in the metadata of an installed plugin.
+	ALIAS_SOURCE_PLUGIN = "plugin"
+)
+
+const (
+	// CONFIG_TYPE_STRING is used for plugin config keys that take any value.
+	CONFIG_TYPE_STRING = "string"
+	// CONFIG_TYPE_BOOL is used for plugin config keys that are true or false.
+	CONFIG_TYPE_BOOL = "bool"
+	// CONFIG_TYPE_INT is used for plugin config keys that are integers.
+	CONFIG_TYPE_INT = "int"
+)
+
+// CONFIG_TYPES contains the valid types of plugin config keys.
+var CONFIG_TYPES = []string{CONFIG_TYPE_STRING, CONFIG_TYPE_BOOL, CONFIG_TYPE_INT}
+
+const (
+	// WINDOWS_DEFAULT_PATHEXT contains the extensions of executable files on Windows when PATHEXT is not set.
+	WINDOWS_DEFAULT_PATHEXT = ".COM;.EXE;.BAT;.CMD"
 )
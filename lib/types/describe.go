@@ -0,0 +1,52 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+// PluginDescription is printed as JSON by plugins that support the introspection protocol
+// when they are run as "konveyor-<name> __konveyor_describe".
+type PluginDescription struct {
+	ApiVersion string `yaml:"apiVersion" json:"apiVersion"`
+	Kind       string `yaml:"kind" json:"kind"`
+	// Spec describes the plugin itself. Its name is ignored.
+	Spec CommandDescription `yaml:"spec" json:"spec"`
+}
+
+// CommandDescription describes a command of a plugin.
+type CommandDescription struct {
+	Name    string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Short   string   `yaml:"short,omitempty" json:"short,omitempty"`
+	Long    string   `yaml:"long,omitempty" json:"long,omitempty"`
+	// Args are the values completed for the positional args. Files are completed if it is empty.
+	Args     []string             `yaml:"args,omitempty" json:"args,omitempty"`
+	Flags    []FlagDescription    `yaml:"flags,omitempty" json:"flags,omitempty"`
+	Commands []CommandDescription `yaml:"commands,omitempty" json:"commands,omitempty"`
+}
+
+// FlagDescription describes a flag of a plugin command.
+type FlagDescription struct {
+	Name      string `yaml:"name" json:"name"`
+	Shorthand string `yaml:"shorthand,omitempty" json:"shorthand,omitempty"`
+	// Type is the type of the value. Example: string, int, stringSlice. Flags of type bool don't take a value.
+	Type    string `yaml:"type,omitempty" json:"type,omitempty"`
+	Usage   string `yaml:"usage,omitempty" json:"usage,omitempty"`
+	Default string `yaml:"default,omitempty" json:"default,omitempty"`
+	// Values are the values completed for the flag. Files are completed if it is empty.
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+	// Persistent flags are also accepted by all the subcommands.
+	Persistent bool `yaml:"persistent,omitempty" json:"persistent,omitempty"`
+}
@@ -21,6 +21,12 @@ import (
 	"fmt"
 )
 
+// PluginExitError is returned when a plugin exits with a non-zero exit code.
+// Konveyor exits with the same code without printing an error of its own.
+type PluginExitError struct {
+	Code int
+}
+
 type RequestError struct {
 	StatusCode int
 	Err        error
@@ -31,8 +37,25 @@ var (
 	ErrPluginNotInstalled = errors.New("the plugin is not installed")
 	// ErrPluginAlreadyInstalled is returned if we try to install an already installed plugin.
 	ErrPluginAlreadyInstalled = errors.New("the plugin is already installed")
+	// ErrUnmetRequirements is returned if the requirements of a plugin are not satisfied.
+	ErrUnmetRequirements = errors.New("the requirements of the plugin are not satisfied")
+	// ErrPluginNotFound is returned if we can't find a plugin in the local cache or on the PATH.
+	ErrPluginNotFound = errors.New("the plugin was not found")
+	// ErrConfigKeyNotFound is returned if the plugin doesn't declare the config key.
+	ErrConfigKeyNotFound = errors.New("the plugin does not declare the config key")
+	// ErrAliasNotFound is returned if there is no alias with the given name.
+	ErrAliasNotFound = errors.New("the alias was not found")
+	// ErrNoPluginDescription is returned if the plugin doesn't describe its commands.
+	ErrNoPluginDescription = errors.New("the plugin does not describe its commands")
+	// ErrSandboxUnavailable is returned if the plugin can't be run in a sandbox on this platform.
+	ErrSandboxUnavailable = errors.New("the sandbox is not available")
 )
 
+// Error returns the string version of the error.
+func (e *PluginExitError) Error() string {
+	return fmt.Sprintf("the plugin exited with the code %d", e.Code)
+}
+
 // Error returns the string version of the error.
 func (e *RequestError) Error() string {
 	return fmt.Sprintf("Status %d: Error: %q", e.StatusCode, e.Err)
@@ -0,0 +1,48 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+import "time"
+
+// HistoryEntry is a run of a plugin recorded in the history.
+type HistoryEntry struct {
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
+	Plugin    string    `yaml:"plugin" json:"plugin"`
+	Version   string    `yaml:"version,omitempty" json:"version,omitempty"`
+	// Path is the executable or the container image of the plugin.
+	Path string `yaml:"path" json:"path"`
+	// Args are the args passed to the plugin with any secrets redacted.
+	Args       []string `yaml:"args" json:"args"`
+	Cwd        string   `yaml:"cwd" json:"cwd"`
+	ExitCode   int      `yaml:"exitCode" json:"exitCode"`
+	DurationMs int64    `yaml:"durationMs" json:"durationMs"`
+	// Error is set if the plugin could not be started.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// HistoryFilter selects the entries of the history. The zero value selects every entry.
+type HistoryFilter struct {
+	Plugin string
+	// Since excludes the entries before this time.
+	Since time.Time
+	// Cwd only selects the runs in this directory or the directories under it.
+	Cwd string
+	// Failed only selects the runs that exited with a non-zero exit code.
+	Failed bool
+	// Limit selects only the most recent entries if it is greater than 0.
+	Limit int
+}
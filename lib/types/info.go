@@ -20,11 +20,29 @@ package types
 type PluginInfo struct {
 	Name               string   `yaml:"name"`
 	Description        string   `yaml:"description"`
+	Aliases            []string `yaml:"aliases,omitempty"`
 	Installed          bool     `yaml:"installed"`
 	InstalledVersion   string   `yaml:"installed-version,omitempty"`
+	LinkedPath         string   `yaml:"linked-path,omitempty"`
 	HomePage           string   `yaml:"home-page,omitempty"`
 	Documentation      string   `yaml:"documentation,omitempty"`
 	Tutorials          string   `yaml:"tutorials,omitempty"`
 	VersionsAvailable  []string `yaml:"versions-available,omitempty"`
 	PlatformsSupported []string `yaml:"platforms-supported,omitempty"`
+	// Permissions are requested by the installed version, or the latest version if the plugin is not installed.
+	Permissions *PluginPermissions `yaml:"permissions,omitempty"`
+}
+
+// PluginVersionInfo describes the version of a plugin that can be run.
+type PluginVersionInfo struct {
+	Name     string `yaml:"name" json:"name"`
+	Version  string `yaml:"version,omitempty" json:"version,omitempty"`
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// Source is where the plugin comes from. One of installed, linked or path
+	Source string `yaml:"source" json:"source"`
+	Path   string `yaml:"path" json:"path"`
+	// ReportedVersion is the output of the plugin's own version command, if it declares one.
+	ReportedVersion string `yaml:"reportedVersion,omitempty" json:"reportedVersion,omitempty"`
+	// Error is set if the plugin's own version command failed.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
 }
@@ -37,12 +37,88 @@ type PluginMetadataSpec struct {
 	ShortDescription string                  `yaml:"shortDescription"`
 	Description      string                  `yaml:"description"`
 	Versions         []PluginVersionMetadata `yaml:"versions"`
+	// Aliases are other names the plugin can be run with. Example: [ttg, tkltest]
+	Aliases []string `yaml:"aliases,omitempty"`
+	// Config contains the keys the user can set using "konveyor plugin config <name> set <key> <value>".
+	Config []PluginConfigOption `yaml:"config,omitempty"`
+}
+
+// PluginConfigOption is a key of the plugin's configuration.
+type PluginConfigOption struct {
+	Name string `yaml:"name" json:"name"`
+	// Type is one of string (the default), bool and int.
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Default     string `yaml:"default,omitempty" json:"default,omitempty"`
+	// Values are the only values allowed for the key, if not empty.
+	Values []string `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+// PluginConfigValue is the value of a key of the plugin's configuration.
+type PluginConfigValue struct {
+	PluginConfigOption `yaml:",inline"`
+	// Value is the value set by the user, if any.
+	Value string `yaml:"value,omitempty" json:"value,omitempty"`
+	// IsSet is true if the user set the value.
+	IsSet bool `yaml:"isSet" json:"isSet"`
 }
 
 // PluginVersionMetadata stores the metadata of a specific version of the plugin.
 type PluginVersionMetadata struct {
 	Version   string                     `yaml:"version"`
 	Platforms []PluginVersionForPlatform `yaml:"platforms"`
+	// Env contains the default values for environment variables passed to the plugin.
+	// Variables already set in the user's environment are not overridden.
+	Env map[string]string `yaml:"env,omitempty"`
+	// Requires contains the requirements that must be satisfied to install and run this version.
+	Requires PluginRequirements `yaml:"requires,omitempty"`
+	// VersionArgs are the arguments that make the plugin print its own version. Example: [version]
+	VersionArgs []string `yaml:"versionArgs,omitempty"`
+	// Permissions are what the plugin needs access to. They are shown to the user for approval when the plugin is installed.
+	Permissions PluginPermissions `yaml:"permissions,omitempty"`
+}
+
+// PluginPermissions contains what a plugin needs access to beyond the current directory.
+type PluginPermissions struct {
+	// Network is true if the plugin needs to access the network.
+	Network bool `yaml:"network,omitempty"`
+	// Paths are the files and directories outside the current directory that the plugin needs to write to. Example: ~/.kube
+	Paths []string `yaml:"paths,omitempty"`
+	// Env are the names of the environment variables the plugin needs to receive. A trailing * matches any suffix. Example: AWS_*
+	Env []string `yaml:"env,omitempty"`
+}
+
+// Sandbox describes the restrictions a plugin is run with.
+type Sandbox struct {
+	// Network is true if the plugin is allowed to access the network.
+	Network bool
+	// WritablePaths are the only files and directories the plugin is allowed to write to.
+	WritablePaths []string
+}
+
+// SandboxOptions control how much of the user's environment the installed plugins get access to.
+type SandboxOptions struct {
+	// PassAllEnv passes the user's whole environment to the plugins instead of only the allowed variables.
+	PassAllEnv bool
+	// Enabled runs the installed plugins in a sandbox when the platform supports it.
+	Enabled bool
+}
+
+// PluginRequirements contains the requirements of a specific version of the plugin.
+type PluginRequirements struct {
+	// Konveyor is a constraint on the version of konveyor. Example: >= v0.2.0, < v1.0.0
+	Konveyor string `yaml:"konveyor,omitempty"`
+	// Binaries are the names of the executables that must be on the PATH. Example: docker
+	Binaries []string `yaml:"binaries,omitempty"`
+	// Plugins are the other plugins that must be installed. They are installed along with this plugin.
+	Plugins []PluginDependency `yaml:"plugins,omitempty"`
+}
+
+// PluginDependency is another plugin that must be installed.
+type PluginDependency struct {
+	Name string `yaml:"name"`
+	// Version is a constraint on the version of the plugin. Example: >= v0.3.0
+	Version string `yaml:"version,omitempty"`
 }
 
 // PluginVersionForPlatform contains the version and platform specific metadata.
@@ -51,15 +127,91 @@ type PluginVersionForPlatform struct {
 	Uri      string   `yaml:"uri"`
 	Sha256   string   `yaml:"sha256"`
 	Bin      string   `yaml:"bin"`
+	// Image is a container image that is run instead of downloading an archive. Example: quay.io/konveyor/move2kube:v0.3.4
+	// When it is set the uri, sha256 and bin are not used.
+	Image string `yaml:"image,omitempty"`
+	// Interpreter runs the bin, which doesn't have to be executable, followed by an optional version constraint.
+	// Example: sh, python3 >=3.8, node >= 16
+	Interpreter string `yaml:"interpreter,omitempty"`
+	// PostInstall is run after the plugin is extracted. Example: to pull container images
+	PostInstall *PluginHook `yaml:"postInstall,omitempty"`
+	// PreUninstall is run before the plugin is removed.
+	PreUninstall *PluginHook `yaml:"preUninstall,omitempty"`
+	// Check is run by "konveyor plugin check" to verify that the plugin works.
+	Check *PluginHook `yaml:"check,omitempty"`
+}
+
+// PluginHook is a command run at some point in the lifecycle of a plugin.
+// The command is run in the directory containing the plugin's files with the same environment as the plugin.
+type PluginHook struct {
+	// Command is the executable and its arguments. Relative paths are relative to the directory containing the plugin's files.
+	Command []string `yaml:"command"`
+	// Timeout is the maximum time the command is given to run. Example: 5m
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// HookOptions control how the plugin hooks are run.
+type HookOptions struct {
+	// Disabled skips all the hooks.
+	Disabled bool
+	// AssumeYes runs the hooks and grants the permissions requested by plugins without asking for confirmation.
+	AssumeYes bool
 }
 
 // Selector contains the platform selector.
+// A platform is selected only if it matches all the labels and all the expressions.
 type Selector struct {
-	MatchLabels MatchLabels `yaml:"matchLabels"`
+	MatchLabels      MatchLabels       `yaml:"matchLabels"`
+	MatchExpressions []MatchExpression `yaml:"matchExpressions,omitempty"`
 }
 
 // MatchLabels contains the platform selector.
 type MatchLabels struct {
 	Os   string `yaml:"os"`
 	Arch string `yaml:"arch"`
+	// Variant is the variant of the architecture. Example: v7 for arm
+	Variant string `yaml:"variant,omitempty"`
+	// Libc is the C standard library on Linux. Either glibc or musl
+	Libc string `yaml:"libc,omitempty"`
+}
+
+// MatchExpression matches the value of a platform label against a list of values.
+type MatchExpression struct {
+	// Key is one of os, arch, variant or libc
+	Key string `yaml:"key"`
+	// Operator is either In or NotIn
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values"`
+}
+
+// Platform describes the platform a plugin runs on.
+type Platform struct {
+	Os      string
+	Arch    string
+	Variant string
+	Libc    string
+}
+
+// PlatformCandidate is a platform we can run plugins built for.
+type PlatformCandidate struct {
+	Platform Platform
+	// Warning is shown when a plugin built for this platform is selected. Empty for the native platform.
+	Warning string
+}
+
+// DiscoveredPlugin is a plugin that can be run, either from the local cache or from the PATH.
+type DiscoveredPlugin struct {
+	// Name is the name of the command used to run the plugin.
+	Name string
+	// Path is the path to the plugin's executable.
+	Path string
+	// Installed contains the local cache entry if the plugin was installed, otherwise nil.
+	Installed *InstalledPlugin
+}
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	Name string
+	// Uri is the URL to download the file or its path on the local filesystem.
+	Uri string
 }
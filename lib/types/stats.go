@@ -0,0 +1,51 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+import "time"
+
+// StatsFile contains the usage statistics of the plugins. They are only stored locally.
+type StatsFile struct {
+	ApiVersion string       `yaml:"apiVersion"`
+	Kind       string       `yaml:"kind"`
+	Metadata   MetadataInfo `yaml:"metadata"`
+	Spec       StatsSpec    `yaml:"spec"`
+}
+
+// StatsSpec contains the usage statistics of each plugin.
+type StatsSpec struct {
+	Plugins []PluginStats `yaml:"plugins"`
+}
+
+// PluginStats contains the usage statistics of a plugin.
+type PluginStats struct {
+	Name     string    `yaml:"name" json:"name"`
+	Runs     int       `yaml:"runs" json:"runs"`
+	LastUsed time.Time `yaml:"lastUsed" json:"lastUsed"`
+	// TimedRuns is the number of runs whose duration is known.
+	// Runs where konveyor is replaced by the plugin process can't be timed.
+	TimedRuns       int   `yaml:"timedRuns" json:"timedRuns"`
+	TotalDurationMs int64 `yaml:"totalDurationMs" json:"totalDurationMs"`
+}
+
+// AverageDuration returns the average duration of the timed runs, 0 if there are none.
+func (s PluginStats) AverageDuration() time.Duration {
+	if s.TimedRuns == 0 {
+		return 0
+	}
+	return time.Duration(s.TotalDurationMs/int64(s.TimedRuns)) * time.Millisecond
+}
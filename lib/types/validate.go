@@ -0,0 +1,28 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package types
+
+// Diagnostic is a problem found while validating a plugin YAML.
+type Diagnostic struct {
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// Path is the location of the problem in the YAML. Example: spec.versions[0].platforms[1].sha256
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// Line is the line number of the problem in the file, if known.
+	Line     int    `yaml:"line,omitempty" json:"line,omitempty"`
+	Severity string `yaml:"severity" json:"severity"`
+	Message  string `yaml:"message" json:"message"`
+}
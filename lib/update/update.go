@@ -0,0 +1,182 @@
+/*
+ *  Copyright IBM Corporation 2022
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package update
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/konveyor/cli/lib"
+	"github.com/konveyor/cli/lib/common"
+	"github.com/konveyor/cli/lib/github"
+	"github.com/konveyor/cli/lib/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// RELEASE_REPO_OWNER is the username of the owner of the Github repo where konveyor is released.
+	RELEASE_REPO_OWNER = "konveyor"
+	// RELEASE_REPO_NAME is the name of the Github repo where konveyor is released.
+	RELEASE_REPO_NAME = "cli"
+	// BIN_NAME is the name of the konveyor executable in the release archives.
+	BIN_NAME = "konveyor"
+)
+
+// GetLatestVersion returns the version of the latest release of konveyor.
+func GetLatestVersion() (string, error) {
+	return github.GetLatestReleaseTag(RELEASE_REPO_OWNER, RELEASE_REPO_NAME)
+}
+
+// IsNewer returns true if the version is newer than the running version of konveyor.
+func IsNewer(version string) bool {
+	return semver.Compare(common.NormalizeVersion(version), common.NormalizeVersion(lib.GetVersion())) > 0
+}
+
+// getReleaseArchiveName returns the name of the release archive for the current platform.
+// The archives are created by scripts/dist/builddist.go
+func getReleaseArchiveName(version string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.tar.gz", BIN_NAME, version, runtime.GOOS, runtime.GOARCH)
+}
+
+// getExecutablePath returns the path to the running konveyor executable with any symbolic links resolved.
+func getExecutablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the path to the konveyor executable. Error: %w", err)
+	}
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the path to the konveyor executable. Error: %w", err)
+	}
+	return exePath, nil
+}
+
+// SelfUpdate replaces the running konveyor executable with the given version. The latest version is used if version is empty.
+// Returns the version that was installed.
+func SelfUpdate(version string) (string, error) {
+	if version == "" {
+		latest, err := GetLatestVersion()
+		if err != nil {
+			return "", err
+		}
+		version = latest
+	}
+	version = common.NormalizeVersion(version)
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("the version '%s' is not a valid semantic version", version)
+	}
+	exePath, err := getExecutablePath()
+	if err != nil {
+		return "", err
+	}
+	// On Windows the old executable can't be removed while it is running, so it is removed during the next update.
+	os.Remove(exePath + ".old")
+	archiveName := getReleaseArchiveName(version)
+	logrus.Infof("Looking for the release archive %s", archiveName)
+	assets, err := github.GetReleaseAssets(RELEASE_REPO_OWNER, RELEASE_REPO_NAME, version)
+	if err != nil {
+		return "", err
+	}
+	archiveIdx := common.FindIndex(func(a types.ReleaseAsset) bool { return a.Name == archiveName }, assets)
+	if archiveIdx == -1 {
+		return "", fmt.Errorf("the release '%s' does not have an archive for the platform %s/%s", version, runtime.GOOS, runtime.GOARCH)
+	}
+	checkSumIdx := common.FindIndex(func(a types.ReleaseAsset) bool { return a.Name == archiveName+types.CHECKSUM_FILE_SUFFIX }, assets)
+	if checkSumIdx == -1 {
+		return "", fmt.Errorf("the release '%s' does not have a checksum for the archive %s", version, archiveName)
+	}
+	checkSumContent, err := github.GetContents(assets[checkSumIdx].Uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the checksum of the archive %s . Error: %w", archiveName, err)
+	}
+	checkSumFields := strings.Fields(string(checkSumContent))
+	if len(checkSumFields) == 0 {
+		return "", fmt.Errorf("the checksum file for the archive %s is empty", archiveName)
+	}
+	tempDir, err := os.MkdirTemp("", "konveyor-update-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary directory. Error: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+	archivePath := filepath.Join(tempDir, archiveName)
+	if err := github.Download(assets[archiveIdx].Uri, archivePath, checkSumFields[0]); err != nil {
+		return "", fmt.Errorf("failed to download the release archive. Error: %w", err)
+	}
+	if err := github.ExtractTarGz(archivePath); err != nil {
+		return "", fmt.Errorf("failed to extract the release archive. Error: %w", err)
+	}
+	binName := BIN_NAME
+	if runtime.GOOS == "windows" {
+		binName += ".exe"
+	}
+	newBinPath := filepath.Join(tempDir, BIN_NAME, binName)
+	if err := replaceExecutable(exePath, newBinPath); err != nil {
+		return "", err
+	}
+	return version, nil
+}
+
+// replaceExecutable replaces the executable at exePath with the one at newBinPath.
+// The new executable is first copied next to the old one so that it can be swapped in using a rename.
+func replaceExecutable(exePath, newBinPath string) error {
+	stagedPath := filepath.Join(filepath.Dir(exePath), "."+filepath.Base(exePath)+".new")
+	if err := copyFile(newBinPath, stagedPath); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("no permission to write to the directory %s . Try running the command as an administrator. Error: %w", filepath.Dir(exePath), err)
+		}
+		return err
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Rename(stagedPath, exePath); err != nil {
+			os.Remove(stagedPath)
+			return fmt.Errorf("failed to replace the executable at path %s . Error: %w", exePath, err)
+		}
+		return nil
+	}
+	// A running executable can't be overwritten on Windows but it can be renamed.
+	oldPath := exePath + ".old"
+	if err := os.Rename(exePath, oldPath); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to move the executable at path %s out of the way. Error: %w", exePath, err)
+	}
+	if err := os.Rename(stagedPath, exePath); err != nil {
+		if rerr := os.Rename(oldPath, exePath); rerr != nil {
+			logrus.Errorf("failed to restore the executable. It can be found at path %s . Error: %q", oldPath, rerr)
+		}
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to replace the executable at path %s . Error: %w", exePath, err)
+	}
+	return nil
+}
+
+// copyFile copies the file to the target path and makes it executable.
+func copyFile(source, target string) error {
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("failed to read the file at path %s . Error: %w", source, err)
+	}
+	if err := os.WriteFile(target, content, 0755); err != nil {
+		return fmt.Errorf("failed to write the file at path %s . Error: %w", target, err)
+	}
+	return nil
+}
@@ -25,15 +25,15 @@ import (
 // VersionInfo describes the compile time information.
 type VersionInfo struct {
 	// Version is the current semver.
-	Version string `yaml:"version,omitempty"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
 	// GitCommit is the git sha1.
-	GitCommit string `yaml:"gitCommit,omitempty"`
+	GitCommit string `yaml:"gitCommit,omitempty" json:"gitCommit,omitempty"`
 	// GitTreeState is the state of the git tree.
-	GitTreeState string `yaml:"gitTreeState,omitempty"`
+	GitTreeState string `yaml:"gitTreeState,omitempty" json:"gitTreeState,omitempty"`
 	// GoVersion is the version of the Go compiler used.
-	GoVersion string `yaml:"goVersion,omitempty"`
+	GoVersion string `yaml:"goVersion,omitempty" json:"goVersion,omitempty"`
 	// Platform gives the OS and ISA the app is running on
-	Platform string `yaml:"platform,omitempty"`
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
 }
 
 var (
@@ -17,12 +17,21 @@
 package main
 
 import (
+	"errors"
+	"os"
+
 	"github.com/konveyor/cli/cmd"
+	"github.com/konveyor/cli/lib/types"
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		// The plugin has already reported its own failure.
+		var exitErr *types.PluginExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		logrus.Fatalf("Error: %q", err)
 	}
 }